@@ -3,11 +3,12 @@
 package chain
 
 import (
+	"github.com/angelorc/sinfonia-go/utility"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
 func (c *Client) EncodeBech32AccAddr(addr sdk.AccAddress) (string, error) {
-	return sdk.Bech32ifyAddressBytes(c.config.AccountPrefix, addr)
+	return utility.EncodeAddressBytes(c.config.AccountPrefix, addr)
 }
 func (c *Client) MustEncodeAccAddr(addr sdk.AccAddress) string {
 	enc, err := c.EncodeBech32AccAddr(addr)
@@ -70,13 +70,17 @@ func (c *Client) ChainID() string {
 	return c.config.ChainID
 }
 
-func (c *Client) LatestBlockHeight(ctx context.Context) int64 {
+func (c *Client) LatestBlockHeight(ctx context.Context) (int64, error) {
 	status, err := c.rpc.Status(ctx)
 	if err != nil {
-		return 0
+		return 0, err
 	}
 
-	return status.SyncInfo.LatestBlockHeight
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+func (c *Client) SubscribeNewBlocks(ctx context.Context) (<-chan int64, error) {
+	return tmcli.SubscribeNewBlocks(ctx, c.rpc, fmt.Sprintf("sinfonia-%s", c.config.ChainID))
 }
 
 func (c *Client) QueryBlock(ctx context.Context, height *int64) (*coretypes.ResultBlock, error) {
@@ -97,7 +101,7 @@ func (c *Client) QueryTx(ctx context.Context, hash []byte) (*tx.Tx, *sdk.TxRespo
 		var stdMsg sdk.Msg
 		err = c.codec.Marshaler.UnpackAny(msg, &stdMsg)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error while unpacking message: %s", err)
+			return nil, nil, fmt.Errorf("%w: error while unpacking message: %s", indexertypes.ErrDecodeTx, err)
 		}
 	}
 
@@ -3,11 +3,16 @@ package cmd
 import "github.com/spf13/cobra"
 
 const (
-	flagModules     = "modules"
-	flagConcurrent  = "concurrent"
-	flagStartHeight = "start-height"
-	flagEndHeight   = "end-height"
-	flagConfig      = "config"
+	flagModules      = "modules"
+	flagConcurrent   = "concurrent"
+	flagStartHeight  = "start-height"
+	flagEndHeight    = "end-height"
+	flagConfig       = "config"
+	flagFollow       = "follow"
+	flagPollInterval = "poll-interval"
+	flagPrefix       = "prefix"
+	flagOutput       = "output"
+	flagImport       = "import"
 )
 
 func addConfigFlag(cmd *cobra.Command) {
@@ -10,8 +10,11 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/repository"
 	"github.com/spf13/cobra"
 	"log"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
 func IndexerCmd() *cobra.Command {
@@ -76,7 +79,10 @@ func GetIndexerParserCmd() *cobra.Command {
 			}
 
 			if endHeight <= startHeight {
-				endHeight = client.LatestBlockHeight(context.Background())
+				endHeight, err = client.LatestBlockHeight(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to get the latest block height: %w", err)
+				}
 			}
 
 			concurrent, err := cmd.Flags().GetInt(flagConcurrent)
@@ -93,9 +99,39 @@ func GetIndexerParserCmd() *cobra.Command {
 				return fmt.Errorf("indicate modules to parse")
 			}
 
-			indexer.
-				NewIndexer(client, parseModules(modulesStr), concurrent).
-				Parse(startHeight, endHeight)
+			idx := indexer.NewIndexer(client, parseModules(modulesStr), concurrent)
+
+			follow, err := cmd.Flags().GetBool(flagFollow)
+			if err != nil {
+				return err
+			}
+
+			// the blocks skipped by the previous runs are below the start height
+			if err := idx.RetrySkipped(); err != nil {
+				return err
+			}
+
+			if follow {
+				pollInterval, err := cmd.Flags().GetDuration(flagPollInterval)
+				if err != nil {
+					return err
+				}
+
+				ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+				defer stop()
+
+				return idx.Follow(ctx, startHeight, pollInterval, func(_, _ int64) error {
+					return syncDerived(client, cfg.Bitsong.ChainID)
+				})
+			}
+
+			if err := idx.Parse(startHeight, endHeight); err != nil {
+				return err
+			}
+
+			if skipped := idx.SkippedBlocks(); len(skipped) > 0 {
+				log.Printf("skipped %d blocks that could not be decoded: %v", len(skipped), skipped)
+			}
 
 			return nil
 		},
@@ -106,12 +142,28 @@ func GetIndexerParserCmd() *cobra.Command {
 
 	cmd.Flags().String(flagModules, "*", "modules to parse eg: * for all or \"blocks,transactions,messages,block-results\" ")
 	cmd.Flags().Int(flagConcurrent, 2, "how many concurrent indexer (do not abuse!)")
+	cmd.Flags().Bool(flagFollow, false, "keep indexing new blocks as they are produced, end-height is ignored")
+	cmd.Flags().Duration(flagPollInterval, 6*time.Second, "how often to poll the chain head when following, used as fallback for the websocket")
 
 	addConfigFlag(cmd)
 
 	return cmd
 }
 
+// syncDerived runs the syncs deriving the bitsong modules from the indexed
+// blocks, the claims after the merkledrops they belong to.
+func syncDerived(client *chain.Client, chainID string) error {
+	if err := syncFantokens(chainID); err != nil {
+		return err
+	}
+
+	if err := syncMerkledrops(client); err != nil {
+		return err
+	}
+
+	return syncMerkledropClaims(chainID)
+}
+
 func parseModules(flag string) *indexer.IndexModules {
 	modulesStr := strings.Split(flag, ",")
 	modules := &indexer.IndexModules{}
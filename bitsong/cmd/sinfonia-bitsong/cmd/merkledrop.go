@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bitsong "github.com/angelorc/sinfonia-go/bitsong/chain"
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/utility"
+	"github.com/spf13/cobra"
+)
+
+func MerkledropCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merkledrop",
+		Short: "merkledrop tools",
+	}
+
+	cmd.AddCommand(GetMerkledropBuildCmd())
+
+	return cmd
+}
+
+func GetMerkledropBuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "build [allocations]",
+		Short: "build the merkle tree of an address,amount csv or json, the root and the proofs list uploaded with updateMerkledrop",
+		Long: `Build the merkle tree of the allocations of a merkledrop.
+
+The allocations are a csv of address,amount rows, with an optional address,amount header, or
+a json list of {"address": "", "amount": ""} or a json object of address: amount.
+The index of an allocation is its position, the json objects are sorted by address.
+
+The proofs list is written to --output, the root and the total are the merkle_root
+and the amount of the merkledrop to create. With --import the proofs of the created
+merkledrop are stored, when its on-chain root and amount match the built ones.`,
+		Example: "sinfonia-bitsong merkledrop build allocations.csv --output proofs.json --import 12",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix, err := cmd.Flags().GetString(flagPrefix)
+			if err != nil {
+				return err
+			}
+
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+
+			mdID, err := cmd.Flags().GetUint64(flagImport)
+			if err != nil {
+				return err
+			}
+
+			allocations, err := readAllocations(args[0], prefix)
+			if err != nil {
+				return err
+			}
+
+			list, root, total, err := buildMerkledropList(allocations)
+			if err != nil {
+				return err
+			}
+
+			bz, err := json.MarshalIndent(list, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, bz, 0o644); err != nil {
+				return err
+			}
+
+			fmt.Printf("merkle root: %s\nentries: %d\ntotal: %s\nproofs: %s\n", root, len(list), total, output)
+
+			if mdID == 0 {
+				return nil
+			}
+
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			/**
+			 * Connect to db
+			 */
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			client, err := bitsong.NewClient(&cfg.Bitsong)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "bitsong", err)
+			}
+
+			return importMerkledropProofs(client, mdID, list, root, total)
+		},
+	}
+
+	cmd.Flags().String(flagPrefix, "bitsong", "bech32 prefix of the addresses")
+	cmd.Flags().String(flagOutput, "./proofs.json", "path of the proofs list")
+	cmd.Flags().Uint64(flagImport, 0, "id of the merkledrop to store the proofs of")
+	addConfigFlag(cmd)
+
+	return cmd
+}
+
+// allocation is the amount of an address in a merkledrop.
+type allocation struct {
+	Address string
+	Amount  *big.Int
+}
+
+// merkledropListItem is an entry of the proofs list uploaded with
+// updateMerkledrop, the list maps the addresses to their entry.
+type merkledropListItem struct {
+	Index  int64    `json:"index"`
+	Amount string   `json:"amount"`
+	Proof  []string `json:"proof"`
+}
+
+type merkledropList map[string]merkledropListItem
+
+// readAllocations reads the allocations of a csv or json file, the addresses
+// are bech32 addresses with prefix and appear once, the amounts are positive
+// integers.
+func readAllocations(path, prefix string) ([]allocation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var allocations []allocation
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		allocations, err = readCSVAllocations(file)
+	case ".json":
+		allocations, err = readJSONAllocations(file)
+	default:
+		return nil, fmt.Errorf("unsupported allocations file %s, expected a .csv or .json", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(allocations) == 0 {
+		return nil, fmt.Errorf("no allocations in %s", path)
+	}
+
+	seen := make(map[string]int, len(allocations))
+	for i, a := range allocations {
+		if _, err := utility.AddressKey(a.Address); err != nil {
+			return nil, fmt.Errorf("allocation %d: %w", i, err)
+		}
+		if !strings.HasPrefix(a.Address, prefix+"1") {
+			return nil, fmt.Errorf("allocation %d: address %s without the %s prefix", i, a.Address, prefix)
+		}
+		if a.Amount.Sign() <= 0 || !a.Amount.IsInt64() {
+			return nil, fmt.Errorf("allocation %d: invalid amount %s of %s", i, a.Amount, a.Address)
+		}
+		if j, ok := seen[a.Address]; ok {
+			return nil, fmt.Errorf("allocation %d: address %s already in allocation %d", i, a.Address, j)
+		}
+		seen[a.Address] = i
+	}
+
+	return allocations, nil
+}
+
+// readCSVAllocations reads the address,amount rows of a csv, the first row is
+// skipped when it is the address,amount header.
+func readCSVAllocations(r io.Reader) ([]allocation, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make([]allocation, 0, len(records))
+	for i, record := range records {
+		if i == 0 && isCSVAllocationsHeader(record) {
+			continue
+		}
+
+		amount, ok := new(big.Int).SetString(strings.TrimSpace(record[1]), 10)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid amount %s", i+1, record[1])
+		}
+
+		allocations = append(allocations, allocation{Address: strings.TrimSpace(record[0]), Amount: amount})
+	}
+
+	return allocations, nil
+}
+
+func isCSVAllocationsHeader(record []string) bool {
+	return strings.EqualFold(strings.TrimSpace(record[0]), "address") &&
+		strings.EqualFold(strings.TrimSpace(record[1]), "amount")
+}
+
+type jsonAllocation struct {
+	Address string      `json:"address"`
+	Amount  json.Number `json:"amount"`
+}
+
+// readJSONAllocations reads a list of address and amount or an object of
+// address: amount, the amounts are numbers or strings.
+func readJSONAllocations(r io.Reader) ([]allocation, error) {
+	bz, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []jsonAllocation
+	if err := json.Unmarshal(bz, &items); err != nil {
+		var amounts map[string]json.Number
+		if err := json.Unmarshal(bz, &amounts); err != nil {
+			return nil, fmt.Errorf("invalid allocations json: %w", err)
+		}
+
+		for address, amount := range amounts {
+			items = append(items, jsonAllocation{Address: address, Amount: amount})
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Address < items[j].Address })
+	}
+
+	allocations := make([]allocation, len(items))
+	for i, item := range items {
+		amount, ok := new(big.Int).SetString(item.Amount.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("allocation %d: invalid amount %s", i, item.Amount)
+		}
+
+		allocations[i] = allocation{Address: item.Address, Amount: amount}
+	}
+
+	return allocations, nil
+}
+
+// buildMerkledropList builds the merkle tree of the allocations with the leaf
+// hashing of the merkledrop module, and returns the proofs list, the hex
+// root and the total amount.
+func buildMerkledropList(allocations []allocation) (merkledropList, string, *big.Int, error) {
+	leaves := make([][]byte, len(allocations))
+	total := new(big.Int)
+
+	for i, a := range allocations {
+		leaves[i] = utility.MerkledropLeaf(uint64(i), a.Address, a.Amount.String())
+		total.Add(total, a.Amount)
+	}
+
+	tree, err := utility.NewMerkledropTree(leaves)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	list := make(merkledropList, len(allocations))
+	for i, a := range allocations {
+		proof := tree.Proof(i)
+
+		item := merkledropListItem{
+			Index:  int64(i),
+			Amount: a.Amount.String(),
+			Proof:  make([]string, len(proof)),
+		}
+		for j, p := range proof {
+			item.Proof[j] = hex.EncodeToString(p)
+		}
+
+		list[a.Address] = item
+	}
+
+	return list, hex.EncodeToString(tree.Root()), total, nil
+}
+
+// importMerkledropProofs stores the proofs of the merkledrop mdID, its
+// on-chain root and amount must be the built ones and it must have no
+// stored proofs.
+func importMerkledropProofs(client *bitsong.Client, mdID uint64, list merkledropList, root string, total *big.Int) error {
+	res, err := client.QueryMerkledropByID(mdID)
+	if err != nil {
+		return fmt.Errorf("error while fetching merkledropID %d, err: %s", mdID, err.Error())
+	}
+
+	if res.Merkledrop.MerkleRoot != root {
+		return fmt.Errorf("merkledrop %d has merkle root %s, built %s", mdID, res.Merkledrop.MerkleRoot, root)
+	}
+	if res.Merkledrop.Amount.BigInt().Cmp(total) != 0 {
+		return fmt.Errorf("merkledrop %d has amount %s, built %s", mdID, res.Merkledrop.Amount, total)
+	}
+
+	merkledropID := int64(mdID)
+	proof := new(model.MerkledropProof)
+
+	stored, err := proof.Count(&model.MerkledropProofWhere{MerkledropID: &merkledropID})
+	if err != nil {
+		return err
+	}
+	if stored > 0 {
+		return fmt.Errorf("merkledrop %d already has %d proofs", mdID, stored)
+	}
+
+	if err := proof.CreateIndexes(); err != nil {
+		return err
+	}
+
+	proofs := make([]model.MerkledropProofCreate, 0, len(list))
+	for address, item := range list {
+		amount, _ := strconv.ParseInt(item.Amount, 10, 64)
+
+		proofs = append(proofs, model.MerkledropProofCreate{
+			MerkledropID: merkledropID,
+			Address:      address,
+			Index:        item.Index,
+			Amount:       amount,
+			Proofs:       item.Proof,
+			Claimed:      false,
+			CreatedAt:    time.Now(),
+		})
+	}
+
+	if err := proof.StoreMany(proofs); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d proofs of merkledrop %d stored\n", len(proofs), mdID)
+
+	return nil
+}
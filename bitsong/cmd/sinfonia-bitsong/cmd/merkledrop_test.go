@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+const (
+	testAddress1 = "bitsong1cyyzpxplxdzkeea7kwsydadg87357qnan0h9zd"
+	testAddress2 = "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2"
+	testOsmo     = "osmo1cyyzpxplxdzkeea7kwsydadg87357qnahakaks"
+)
+
+func TestReadAllocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []allocation
+		wantErr string
+	}{
+		{
+			name:    "csv",
+			file:    "allocations.csv",
+			content: testAddress1 + ",100\n" + testAddress2 + ", 200\n",
+			want:    []allocation{{testAddress1, big.NewInt(100)}, {testAddress2, big.NewInt(200)}},
+		},
+		{
+			name:    "csv header",
+			file:    "allocations.csv",
+			content: "Address, Amount\n" + testAddress1 + ",100\n",
+			want:    []allocation{{testAddress1, big.NewInt(100)}},
+		},
+		{
+			name:    "csv invalid first amount",
+			file:    "allocations.csv",
+			content: testAddress1 + ",1e6\n" + testAddress2 + ",200\n",
+			wantErr: "line 1: invalid amount 1e6",
+		},
+		{
+			name:    "csv invalid amount",
+			file:    "allocations.csv",
+			content: testAddress1 + ",100\n" + testAddress2 + ",abc\n",
+			wantErr: "line 2: invalid amount abc",
+		},
+		{
+			name:    "csv header only",
+			file:    "allocations.csv",
+			content: "address,amount\n",
+			wantErr: "no allocations",
+		},
+		{
+			name:    "json list",
+			file:    "allocations.json",
+			content: `[{"address": "` + testAddress2 + `", "amount": "200"}, {"address": "` + testAddress1 + `", "amount": 100}]`,
+			want:    []allocation{{testAddress2, big.NewInt(200)}, {testAddress1, big.NewInt(100)}},
+		},
+		{
+			name:    "json object",
+			file:    "allocations.json",
+			content: `{"` + testAddress2 + `": "200", "` + testAddress1 + `": 100}`,
+			want:    []allocation{{testAddress1, big.NewInt(100)}, {testAddress2, big.NewInt(200)}},
+		},
+		{
+			name:    "duplicate address",
+			file:    "allocations.csv",
+			content: testAddress1 + ",100\n" + testAddress2 + ",200\n" + testAddress1 + ",300\n",
+			wantErr: "allocation 2: address " + testAddress1 + " already in allocation 0",
+		},
+		{
+			name:    "wrong prefix",
+			file:    "allocations.csv",
+			content: testOsmo + ",100\n",
+			wantErr: "allocation 0: address " + testOsmo + " without the bitsong prefix",
+		},
+		{
+			name:    "zero amount",
+			file:    "allocations.json",
+			content: `[{"address": "` + testAddress1 + `", "amount": "0"}]`,
+			wantErr: "allocation 0: invalid amount 0",
+		},
+		{
+			name:    "unsupported file",
+			file:    "allocations.txt",
+			content: testAddress1 + ",100\n",
+			wantErr: "unsupported allocations file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := readAllocations(path, "bitsong")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readAllocations() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertAllocations(t, got, tt.want)
+		})
+	}
+}
+
+func TestReadJSONAllocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []allocation
+		wantErr bool
+	}{
+		{
+			name:    "list keeps its order",
+			content: `[{"address": "c", "amount": "3"}, {"address": "a", "amount": 1}, {"address": "b", "amount": "2"}]`,
+			want:    []allocation{{"c", big.NewInt(3)}, {"a", big.NewInt(1)}, {"b", big.NewInt(2)}},
+		},
+		{
+			name:    "object sorted by address",
+			content: `{"c": "3", "a": 1, "b": "2"}`,
+			want:    []allocation{{"a", big.NewInt(1)}, {"b", big.NewInt(2)}, {"c", big.NewInt(3)}},
+		},
+		{
+			name:    "amount above int64",
+			content: `{"a": "18446744073709551616"}`,
+			want:    []allocation{{"a", new(big.Int).Lsh(big.NewInt(1), 64)}},
+		},
+		{
+			name:    "decimal amount",
+			content: `{"a": 1.5}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			content: `"a,1"`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readJSONAllocations(strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readJSONAllocations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assertAllocations(t, got, tt.want)
+		})
+	}
+}
+
+func TestBuildMerkledropList(t *testing.T) {
+	tests := []struct {
+		name        string
+		allocations []allocation
+		wantTotal   int64
+		wantErr     bool
+	}{
+		{
+			name:        "one allocation",
+			allocations: []allocation{{testAddress1, big.NewInt(100)}},
+			wantTotal:   100,
+		},
+		{
+			name:        "two allocations",
+			allocations: []allocation{{testAddress2, big.NewInt(200)}, {testAddress1, big.NewInt(100)}},
+			wantTotal:   300,
+		},
+		{
+			name: "three allocations",
+			allocations: []allocation{
+				{testAddress1, big.NewInt(100)},
+				{testAddress2, big.NewInt(200)},
+				{"bitsong1other", big.NewInt(300)},
+			},
+			wantTotal: 600,
+		},
+		{
+			name:    "no allocations",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, root, total, err := buildMerkledropList(tt.allocations)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildMerkledropList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if total.Int64() != tt.wantTotal {
+				t.Fatalf("total = %s, want %d", total, tt.wantTotal)
+			}
+			if len(list) != len(tt.allocations) {
+				t.Fatalf("%d items, want %d", len(list), len(tt.allocations))
+			}
+
+			rootBz, err := hex.DecodeString(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, a := range tt.allocations {
+				item, ok := list[a.Address]
+				if !ok {
+					t.Fatalf("no item of %s", a.Address)
+				}
+				if item.Index != int64(i) || item.Amount != a.Amount.String() {
+					t.Fatalf("item of %s = %d %s, want %d %s", a.Address, item.Index, item.Amount, i, a.Amount)
+				}
+
+				proof := make([][]byte, len(item.Proof))
+				for j, p := range item.Proof {
+					if proof[j], err = hex.DecodeString(p); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				leaf := utility.MerkledropLeaf(uint64(i), a.Address, a.Amount.String())
+				if !utility.VerifyMerkledropProof(leaf, proof, rootBz) {
+					t.Fatalf("proof of %s does not verify", a.Address)
+				}
+			}
+		})
+	}
+}
+
+func assertAllocations(t *testing.T, got, want []allocation) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%d allocations, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Address != want[i].Address || got[i].Amount.Cmp(want[i].Amount) != 0 {
+			t.Fatalf("allocation %d = %s %s, want %s %s", i, got[i].Address, got[i].Amount, want[i].Address, want[i].Amount)
+		}
+	}
+}
@@ -26,6 +26,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(
 		IndexerCmd(),
 		SyncCmd(),
+		MerkledropCmd(),
 	)
 
 	return rootCmd
@@ -6,12 +6,20 @@ import (
 	"github.com/angelorc/sinfonia-go/config"
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
 	"github.com/spf13/cobra"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"strconv"
 	"strings"
 )
 
+// checkpoint modules of the bitsong syncs
+const (
+	checkpointFantokens        = "fantokens"
+	checkpointMerkledrops      = "merkledrops"
+	checkpointMerkledropProofs = "merkledrop_proofs"
+)
+
 func SyncCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sync",
@@ -56,7 +64,7 @@ func GetSyncFantokenCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			if err := syncFantokens(); err != nil {
+			if err := syncFantokens(cfg.Bitsong.ChainID); err != nil {
 				return err
 			}
 
@@ -116,20 +124,17 @@ func GetSyncMerkledropCmd() *cobra.Command {
 	return cmd
 }
 
-func syncFantokens() error {
+func syncFantokens(chainID string) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight()
+	lastBlock := model.GetLastHeight(chainID)
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	sync.One()
+	// get last block synced
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainID, checkpointFantokens)
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Fantokens = int64(0)
-	}
+	fantokenRepo := repository.NewFantokenRepository()
 
-	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.fantoken.MsgIssue", sync.Fantokens, lastBlock)
+	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.fantoken.MsgIssue", checkpoint.Height, lastBlock)
 	if err != nil {
 		return err
 	}
@@ -154,18 +159,19 @@ func syncFantokens() error {
 					if err := fantoken.Create(data); err != nil {
 						return err
 					}
+
+					pubsub.Publish(pubsub.TopicFantokens, fantokenRepo.FindByDenom(denom))
 				}
 			}
 		}
 	}
 
 	// update sync with last synced height
-	sync.Fantokens = lastBlock
-	if err := sync.Save(); err != nil {
+	if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
 		return err
 	}
 
-	fmt.Printf("%d fantokens synced to block %d ", len(txsLogs), sync.Fantokens)
+	fmt.Printf("%d fantokens synced to block %d ", len(txsLogs), checkpoint.Height)
 
 	return nil
 }
@@ -182,18 +188,13 @@ func getAttrs(attrKey string, attrs []model.Attribute) (value string) {
 
 func syncMerkledrops(client *bitsong.Client) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight()
+	lastBlock := model.GetLastHeight(client.ChainID())
 
 	// get last block synced
-	sync := new(model.Sync)
-	sync.One()
-
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Fantokens = int64(0)
-	}
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(client.ChainID(), checkpointMerkledrops)
 
-	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.merkledrop.v1beta1.MsgCreate", sync.Merkledrops, lastBlock)
+	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.merkledrop.v1beta1.MsgCreate", checkpoint.Height, lastBlock)
 	if err != nil {
 		return err
 	}
@@ -242,12 +243,11 @@ func syncMerkledrops(client *bitsong.Client) error {
 	// then prune
 
 	// update sync with last synced height
-	sync.Merkledrops = lastBlock
-	if err := sync.Save(); err != nil {
+	if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
 		return err
 	}
 
-	fmt.Printf("%d merkledrops synced to block %d ", len(txsLogs), sync.Merkledrops)
+	fmt.Printf("%d merkledrops synced to block %d ", len(txsLogs), checkpoint.Height)
 
 	return nil
 }
@@ -281,7 +281,7 @@ func GetSyncMerkledropClaimCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			if err := syncMerkledropClaims(); err != nil {
+			if err := syncMerkledropClaims(cfg.Bitsong.ChainID); err != nil {
 				return err
 			}
 
@@ -294,24 +294,15 @@ func GetSyncMerkledropClaimCmd() *cobra.Command {
 	return cmd
 }
 
-func syncMerkledropClaims() error {
-
+func syncMerkledropClaims(chainID string) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight()
+	lastBlock := model.GetLastHeight(chainID)
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	err := sync.One()
-	if err != nil {
-		return err
-	}
-
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Fantokens = int64(0)
-	}
+	// get last block synced
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainID, checkpointMerkledropProofs)
 
-	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.merkledrop.v1beta1.MsgClaim", sync.Fantokens, lastBlock)
+	txsLogs, err := model.GetTxsAndLogsByMessageType("/bitsong.merkledrop.v1beta1.MsgClaim", checkpoint.Height, lastBlock)
 	if err != nil {
 		return err
 	}
@@ -330,6 +321,8 @@ func syncMerkledropClaims() error {
 						MerkledropID: merkledropId,
 						Address:      address,
 						Index:        index,
+						Height:       txLogs.Height,
+						Time:         txLogs.Time,
 					}); err != nil {
 						return err
 					}
@@ -339,12 +332,11 @@ func syncMerkledropClaims() error {
 	}
 
 	// update sync with last synced height
-	sync.MerkledropProofs = lastBlock
-	if err := sync.Save(); err != nil {
+	if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
 		return err
 	}
 
-	fmt.Printf("%d merkledrop proofs synced to block %d ", len(txsLogs), sync.MerkledropProofs)
+	fmt.Printf("%d merkledrop proofs synced to block %d ", len(txsLogs), checkpoint.Height)
 
 	return nil
 }
@@ -5,7 +5,10 @@ import (
 )
 
 const (
-	flagConfig = "config"
+	flagConfig        = "config"
+	flagChainID       = "chain-id"
+	flagGenesisHeight = "genesis-height"
+	flagBatchSize     = "batch-size"
 )
 
 func addConfigFlag(cmd *cobra.Command) {
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+func GetMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "database migrations",
+	}
+
+	cmd.AddCommand(
+		GetMigrateCoinsCmd(),
+		GetMigrateAddressesCmd(),
+	)
+
+	return cmd
+}
+
+func GetMigrateCoinsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "coins",
+		Short:   "convert the coins stored with a float amount to exact amounts",
+		Example: "sinfonia migrate coins",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			batchSize, err := cmd.Flags().GetInt(flagBatchSize)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			for _, collection := range repository.CoinCollections {
+				updated, err := repository.MigrateCoins(collection, batchSize)
+				if err != nil {
+					return fmt.Errorf("failed to migrate %s: %w", collection, err)
+				}
+
+				fmt.Printf("%s: %d documents migrated\n", collection, updated)
+			}
+
+			return nil
+		},
+	}
+
+	addConfigFlag(cmd)
+	cmd.Flags().Int(flagBatchSize, 500, "number of documents written at once")
+
+	return cmd
+}
+
+func GetMigrateAddressesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "addresses",
+		Short:   "set the chain independent key of the stored addresses",
+		Example: "sinfonia migrate addresses",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			batchSize, err := cmd.Flags().GetInt(flagBatchSize)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			for _, field := range repository.AddressFields {
+				updated, err := repository.MigrateAddressKeys(field, batchSize)
+				if err != nil {
+					return fmt.Errorf("failed to migrate %s: %w", field.Collection, err)
+				}
+
+				fmt.Printf("%s: %d documents migrated\n", field.Collection, updated)
+			}
+
+			return nil
+		},
+	}
+
+	addConfigFlag(cmd)
+	cmd.Flags().Int(flagBatchSize, 500, "number of documents written at once")
+
+	return cmd
+}
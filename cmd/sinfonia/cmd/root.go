@@ -26,6 +26,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(
 		GetServerCmd(),
 		GetSyncCmd(),
+		GetMigrateCmd(),
 	)
 
 	return rootCmd
@@ -1,11 +1,16 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/angelorc/sinfonia-go/config"
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
 	"github.com/spf13/cobra"
+	"os"
 	"strconv"
+	"text/tabwriter"
+	"time"
 )
 
 func GetSyncCmd() *cobra.Command {
@@ -16,6 +21,9 @@ func GetSyncCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		GetSyncAccountCmd(),
+		GetSyncLeaderboardsCmd(),
+		GetSyncStatusCmd(),
+		GetSyncImportLegacyCmd(),
 	)
 
 	return cmd
@@ -50,10 +58,165 @@ func GetSyncAccountCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			if err := model.SyncAccounts(); err != nil {
+			chainID, err := cmd.Flags().GetString(flagChainID)
+			if err != nil {
+				return err
+			}
+
+			if chainID != "" {
+				cfg.Osmosis.ChainID = chainID
+			}
+
+			genesisHeight, err := cmd.Flags().GetInt64(flagGenesisHeight)
+			if err != nil {
+				return err
+			}
+
+			if genesisHeight < 0 {
+				genesisHeight = cfg.Osmosis.ModuleGenesisHeight("accounts")
+			}
+
+			if err := model.SyncAccounts(cfg.Osmosis.ChainID, genesisHeight); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	addConfigFlag(cmd)
+	cmd.Flags().String(flagChainID, "", "override the osmosis chain-id of the config file")
+	cmd.Flags().Int64(flagGenesisHeight, -1, "override the genesis height of the accounts sync")
+
+	return cmd
+}
+
+func GetSyncStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status",
+		Short:   "show every sync checkpoint and its lag behind the latest indexed block",
+		Example: "sinfonia sync status",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			checkpoints, err := repository.NewCheckpointRepository().Find(nil)
+			if err != nil {
 				return err
 			}
 
+			lastHeights := make(map[string]int64)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHAIN ID\tMODULE\tHEIGHT\tLAST HEIGHT\tLAG\tUPDATED AT")
+
+			for _, c := range checkpoints {
+				lastHeight, ok := lastHeights[c.ChainID]
+				if !ok {
+					lastHeight = model.GetLastHeight(c.ChainID)
+					lastHeights[c.ChainID] = lastHeight
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", c.ChainID, c.Module, c.Height, lastHeight, lastHeight-c.Height, c.UpdatedAt.Format(time.RFC3339))
+			}
+
+			return w.Flush()
+		},
+	}
+
+	addConfigFlag(cmd)
+
+	return cmd
+}
+
+func GetSyncImportLegacyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "import-legacy",
+		Short:   "create the sync checkpoints from the legacy sync document",
+		Example: "sinfonia sync import-legacy",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			sync := new(model.Sync)
+			sync.One()
+
+			if sync.ID.IsZero() {
+				return fmt.Errorf("legacy sync document not found")
+			}
+
+			// the merkledrop claims sync used to read the fantokens cursor and write
+			// the merkledrop_proofs one, restart from the lowest to not miss claims
+			claimsHeight := sync.MerkledropProofs
+			if sync.Fantokens < claimsHeight {
+				claimsHeight = sync.Fantokens
+			}
+
+			cursors := []struct {
+				chainID string
+				module  string
+				height  int64
+			}{
+				{cfg.Osmosis.ChainID, "accounts", sync.Accounts},
+				{cfg.Osmosis.ChainID, "pools", sync.Pools},
+				{cfg.Osmosis.ChainID, "swaps", sync.Swaps},
+				{cfg.Osmosis.ChainID, "incentives", sync.Incentives},
+				{cfg.Osmosis.ChainID, "liquidity_events", sync.LiquidityEvents},
+				{cfg.Bitsong.ChainID, "fantokens", sync.Fantokens},
+				{cfg.Bitsong.ChainID, "merkledrops", sync.Merkledrops},
+				{cfg.Bitsong.ChainID, "merkledrop_proofs", claimsHeight},
+			}
+
+			checkpointRepo := repository.NewCheckpointRepository()
+
+			for _, c := range cursors {
+				checkpoint := checkpointRepo.Get(c.chainID, c.module)
+				if !checkpoint.ID.IsZero() {
+					fmt.Printf("%s %s already at height %d, skipped\n", c.chainID, c.module, checkpoint.Height)
+					continue
+				}
+
+				if err := checkpointRepo.Save(checkpoint, c.height); err != nil {
+					return err
+				}
+
+				fmt.Printf("%s %s imported at height %d\n", c.chainID, c.module, c.height)
+			}
+
 			return nil
 		},
 	}
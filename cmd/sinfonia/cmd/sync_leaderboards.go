@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/spf13/cobra"
+)
+
+const checkpointLeaderboards = "leaderboards"
+
+func GetSyncLeaderboardsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "leaderboards",
+		Short:   "aggregate the daily volume, liquidity and merkledrop claims of every account for the leaderboards",
+		Example: "sinfonia sync leaderboards",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			return syncLeaderboards(cfg)
+		},
+	}
+
+	addConfigFlag(cmd)
+
+	return cmd
+}
+
+func syncLeaderboards(cfg *config.Config) error {
+	leaderboardRepo := repository.NewLeaderboardRepository()
+	leaderboardRepo.EnsureIndexes()
+
+	err := syncLeaderboard(cfg.Osmosis.ChainID, []string{"swaps", "liquidity_events"}, func(from, to time.Time) error {
+		if err := leaderboardRepo.RefreshSwaps(cfg.Osmosis.ChainID, from, to); err != nil {
+			return err
+		}
+
+		return leaderboardRepo.RefreshLiquidity(cfg.Osmosis.ChainID, from, to)
+	})
+	if err != nil {
+		return err
+	}
+
+	return syncLeaderboard(cfg.Bitsong.ChainID, []string{"merkledrop_proofs"}, func(from, to time.Time) error {
+		return leaderboardRepo.RefreshClaims(cfg.Bitsong.ChainID, from, to)
+	})
+}
+
+// syncLeaderboard rebuilds the buckets of the days containing the blocks
+// synced by modules since the last run. The day of the last run is rebuilt
+// again, as it can be partially synced.
+func syncLeaderboard(chainID string, modules []string, refresh func(from, to time.Time) error) error {
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainID, checkpointLeaderboards)
+
+	// the buckets can be built up to the height synced by all the modules
+	lastBlock := int64(math.MaxInt64)
+	for _, module := range modules {
+		if height := checkpointRepo.Get(chainID, module).Height; height < lastBlock {
+			lastBlock = height
+		}
+	}
+
+	if lastBlock <= checkpoint.Height {
+		log.Printf("%s leaderboards synced to block %d", chainID, checkpoint.Height)
+		return nil
+	}
+
+	blockRepo := repository.NewBlockRepository()
+
+	// a checkpoint without a stored block rebuilds all the days
+	var from time.Time
+	if checkpoint.Height > 0 {
+		from = blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &checkpoint.Height}).Time
+	}
+
+	to := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &lastBlock}).Time
+	if to.IsZero() {
+		return fmt.Errorf("block %d of %s not found", lastBlock, chainID)
+	}
+
+	if err := refresh(from, to); err != nil {
+		return fmt.Errorf("failed to refresh the %s leaderboards: %w", chainID, err)
+	}
+
+	if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
+		return err
+	}
+
+	log.Printf("%s leaderboards synced to block %d", chainID, lastBlock)
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/angelorc/sinfonia-go/utility"
 	"gopkg.in/yaml.v2"
 	"os"
+	"time"
 )
 
 type GraphQL struct {
@@ -13,6 +14,49 @@ type GraphQL struct {
 	Port           string `yaml:"port" validate:"required"`
 	Endpoint       string `yaml:"endpoint" validate:"required"`
 	PlaygroundPass string `yaml:"playground_pass"`
+	// ChangeStreams feeds the subscriptions with the documents inserted by an
+	// indexer running in another process, it requires a replica set.
+	ChangeStreams bool `yaml:"change_streams"`
+	Auth          Auth `yaml:"auth"`
+}
+
+// Auth configures the web3tokens accepted by the @auth fields. Domains are
+// the domains the tokens can be signed for, no token is accepted without
+// one. A nonce issued by /auth/nonce can be used by a single token, RequireNonce
+// rejects the tokens without it. NonceLimit is the number of nonces issued per
+// minute to a client IP. Admins are the bech32 addresses having the
+// admin role on every resource, eg: any merkledrop.
+type Auth struct {
+	Domains      []string `yaml:"domains"`
+	Admins       []string `yaml:"admins"`
+	RequireNonce bool     `yaml:"require_nonce"`
+	NonceTTL     string   `yaml:"nonce_ttl"`
+	NonceLimit   int      `yaml:"nonce_limit"`
+}
+
+// DefaultNonceTTL is the validity of the nonces when none is configured.
+const DefaultNonceTTL = 10 * time.Minute
+
+// DefaultNonceLimit is the number of nonces issued per minute to a client IP
+// when none is configured.
+const DefaultNonceLimit = 10
+
+// GetNonceLimit returns the nonce limit, or the default one.
+func (a *Auth) GetNonceLimit() int {
+	if a.NonceLimit == 0 {
+		return DefaultNonceLimit
+	}
+
+	return a.NonceLimit
+}
+
+// GetNonceTTL returns the parsed nonce ttl, or the default one.
+func (a *Auth) GetNonceTTL() (time.Duration, error) {
+	if a.NonceTTL == "" {
+		return DefaultNonceTTL, nil
+	}
+
+	return time.ParseDuration(a.NonceTTL)
 }
 
 type Mongo struct {
@@ -28,6 +72,148 @@ type ChainConfig struct {
 	GRPCInsecure  bool   `yaml:"grpc-insecure" validate:"required"`
 	AccountPrefix string `yaml:"account-prefix" validate:"required"`
 	Timeout       string `yaml:"timeout" validate:"required"`
+
+	// GenesisHeight is the first height synced by the modules, GenesisHeights
+	// overrides it for a single module, eg: swaps: 5112889
+	GenesisHeight  int64            `yaml:"genesis-height"`
+	GenesisHeights map[string]int64 `yaml:"genesis-heights"`
+
+	Assets []AssetConfig `yaml:"assets" validate:"dive"`
+	Prices PricesConfig  `yaml:"prices"`
+}
+
+// AssetConfig describes an asset tracked on the chain. Pools having at least
+// one asset with a quote priority are tracked, when both assets can be used as
+// quote the one with the highest priority is used.
+type AssetConfig struct {
+	Denom         string `yaml:"denom" validate:"required"`
+	Symbol        string `yaml:"symbol" validate:"required"`
+	Decimals      int    `yaml:"decimals" validate:"gte=0,lte=36"`
+	CoingeckoID   string `yaml:"coingecko-id"`
+	QuotePriority int    `yaml:"quote-priority" validate:"gte=0"`
+}
+
+// Price sources
+const (
+	PriceSourceCoingecko = "coingecko"
+	PriceSourceTWAP      = "twap"
+	PriceSourceStatic    = "static"
+)
+
+// DefaultPriceSources is the priority of the price sources when none is
+// configured.
+var DefaultPriceSources = []string{PriceSourceCoingecko, PriceSourceTWAP, PriceSourceStatic}
+
+// PricesConfig configures the sources of the USD prices of the assets.
+// Sources are queried by priority, the first one having a price wins.
+// MaxAge is the max distance of a stored price from the time it values,
+// eg: 2h, older prices are asked again to the sources. Interpolate values
+// the times between two stored prices with a linear interpolation.
+type PricesConfig struct {
+	MaxAge      string `yaml:"max-age"`
+	Interpolate bool   `yaml:"interpolate"`
+
+	Sources   []string        `yaml:"sources" validate:"dive,oneof=coingecko twap static"`
+	Coingecko CoingeckoConfig `yaml:"coingecko"`
+	TWAP      TWAPConfig      `yaml:"twap"`
+	// Static maps a denom to a fixed price, eg: the USD-pegged assets.
+	// StaticFile is a yaml or json file with the same map.
+	Static     map[string]float64 `yaml:"static"`
+	StaticFile string             `yaml:"static-file"`
+}
+
+// CoingeckoConfig uses the pro api when APIKey is set, RateLimit is the
+// number of requests per minute.
+type CoingeckoConfig struct {
+	APIKey    string `yaml:"api-key"`
+	RateLimit int    `yaml:"rate-limit" validate:"gte=0"`
+}
+
+// TWAPConfig prices the assets from the indexed swaps of a tracked pool
+// pairing them with a USD-pegged asset, averaged over Window. A zero window
+// uses the price of the last swap.
+type TWAPConfig struct {
+	Window string           `yaml:"window"`
+	Pools  []TWAPPoolConfig `yaml:"pools" validate:"dive"`
+}
+
+type TWAPPoolConfig struct {
+	Denom  string `yaml:"denom" validate:"required"`
+	PoolID uint64 `yaml:"pool-id" validate:"required"`
+}
+
+// PriceSources returns the configured sources, or the default ones.
+func (c *PricesConfig) PriceSources() []string {
+	if len(c.Sources) == 0 {
+		return DefaultPriceSources
+	}
+
+	return c.Sources
+}
+
+// MaxPriceAge returns the parsed max age of the prices, zero for no limit.
+func (c *PricesConfig) MaxPriceAge() (time.Duration, error) {
+	if c.MaxAge == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(c.MaxAge)
+}
+
+// TWAPWindow returns the parsed TWAP window.
+func (c *PricesConfig) TWAPWindow() (time.Duration, error) {
+	if c.TWAP.Window == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(c.TWAP.Window)
+}
+
+// ModuleGenesisHeight returns the first height to sync for module.
+func (c *ChainConfig) ModuleGenesisHeight(module string) int64 {
+	if height, ok := c.GenesisHeights[module]; ok {
+		return height
+	}
+
+	return c.GenesisHeight
+}
+
+func (c *ChainConfig) Validate() error {
+	if err := utility.ValidateStruct(c); err != nil {
+		return err
+	}
+
+	if _, err := time.ParseDuration(c.Timeout); err != nil {
+		return fmt.Errorf("%s: invalid timeout %s", c.ChainID, c.Timeout)
+	}
+
+	if c.GenesisHeight < 0 {
+		return fmt.Errorf("%s: genesis-height cannot be negative", c.ChainID)
+	}
+
+	for module, height := range c.GenesisHeights {
+		if height < 0 {
+			return fmt.Errorf("%s: genesis height of %s cannot be negative", c.ChainID, module)
+		}
+	}
+
+	if window, err := c.Prices.TWAPWindow(); err != nil || window < 0 {
+		return fmt.Errorf("%s: invalid twap window %s", c.ChainID, c.Prices.TWAP.Window)
+	}
+
+	if maxAge, err := c.Prices.MaxPriceAge(); err != nil || maxAge < 0 {
+		return fmt.Errorf("%s: invalid prices max-age %s", c.ChainID, c.Prices.MaxAge)
+	}
+
+	denoms := make(map[string]bool)
+	for _, asset := range c.Assets {
+		if denoms[asset.Denom] {
+			return fmt.Errorf("%s: asset %s is duplicated", c.ChainID, asset.Denom)
+		}
+		denoms[asset.Denom] = true
+	}
+
+	return nil
 }
 
 type CloudflareConfig struct {
@@ -66,6 +252,22 @@ func NewConfig(configPath string) (*Config, error) {
 		return nil, err
 	}
 
+	if ttl, err := config.GraphQL.Auth.GetNonceTTL(); err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid graphql auth nonce_ttl %s", config.GraphQL.Auth.NonceTTL)
+	}
+
+	if config.GraphQL.Auth.NonceLimit < 0 {
+		return nil, fmt.Errorf("invalid graphql auth nonce_limit %d", config.GraphQL.Auth.NonceLimit)
+	}
+
+	if err := config.Bitsong.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.Osmosis.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
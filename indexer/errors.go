@@ -0,0 +1,87 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies the failures of the indexer, so that the caller can
+// decide whether to retry the block, skip it or abort.
+type ErrorKind int
+
+const (
+	// ErrTransient is a failure talking to the node (timeouts, unavailable
+	// rpc/grpc, block not produced yet), the same block can be retried later.
+	ErrTransient ErrorKind = iota
+	// ErrDecode is a failure decoding the data returned by the node, retrying
+	// will not help, the block should be skipped and recorded.
+	ErrDecode
+	// ErrStorage is a failure writing to the database, indexing must stop.
+	ErrStorage
+	// ErrFork is returned when a fetched block does not match the blocks
+	// already stored, the data above the fork point must be rolled back.
+	ErrFork
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrTransient:
+		return "transient"
+	case ErrDecode:
+		return "decode"
+	case ErrStorage:
+		return "storage"
+	case ErrFork:
+		return "fork"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is the error returned by the indexer while parsing a block.
+type Error struct {
+	Kind   ErrorKind
+	Height int64
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[height %d] %s error: %v", e.Height, e.Kind, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func newError(kind ErrorKind, height int64, format string, args ...interface{}) *Error {
+	return &Error{Kind: kind, Height: height, Err: fmt.Errorf(format, args...)}
+}
+
+// IsTransient reports whether err is a transient error and the block can be retried.
+func IsTransient(err error) bool {
+	return isKind(err, ErrTransient)
+}
+
+// IsDecode reports whether err is a decode error and the block should be skipped.
+func IsDecode(err error) bool {
+	return isKind(err, ErrDecode)
+}
+
+// IsStorage reports whether err is a storage error.
+func IsStorage(err error) bool {
+	return isKind(err, ErrStorage)
+}
+
+// IsFork reports whether err is caused by a chain fork.
+func IsFork(err error) bool {
+	return isKind(err, ErrFork)
+}
+
+func isKind(err error, kind ErrorKind) bool {
+	var idxErr *Error
+	if errors.As(err, &idxErr) {
+		return idxErr.Kind == kind
+	}
+
+	return false
+}
@@ -0,0 +1,110 @@
+package indexer
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Follow indexes the chain from fromBlock up to the current head and then keeps
+// indexing every new block until ctx is cancelled. New heights are received from
+// the websocket subscription, while the chain head is also polled every
+// pollInterval so that the indexer keeps going if the subscription is not
+// available or gets dropped. Ranges failing with a transient error are retried,
+// the skipped blocks are logged after every range and any other error is
+// returned. onIndexed is called after every indexed range and can be used to
+// run the downstream syncs.
+func (i *Indexer) Follow(ctx context.Context, fromBlock int64, pollInterval time.Duration, onIndexed func(fromBlock, toBlock int64) error) error {
+	nextBlock := fromBlock
+	// the skipped blocks already logged
+	reported := len(i.SkippedBlocks())
+
+	// retryTransient logs a transient error, the blocks are indexed again
+	// from the same block on the next tick
+	retryTransient := func(err error) error {
+		if IsTransient(err) {
+			log.Printf("failed to index blocks from %d, retrying. err: %v", nextBlock, err)
+			return nil
+		}
+
+		return err
+	}
+
+	index := func(latest int64) error {
+		if latest < nextBlock {
+			return nil
+		}
+
+		if err := i.Parse(nextBlock, latest); err != nil {
+			return retryTransient(err)
+		}
+
+		if skipped := i.SkippedBlocks(); len(skipped) > reported {
+			log.Printf("skipped %d blocks that could not be decoded: %v", len(skipped)-reported, skipped[reported:])
+			reported = len(skipped)
+		}
+
+		if onIndexed != nil {
+			if err := onIndexed(nextBlock, latest); err != nil {
+				return err
+			}
+		}
+
+		nextBlock = latest + 1
+		return nil
+	}
+
+	// indexHead indexes the blocks up to the chain head, an unreachable node
+	// is a transient error
+	indexHead := func() error {
+		latest, err := i.client.LatestBlockHeight(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return retryTransient(newError(ErrTransient, nextBlock, "failed to get the latest block height: %w", err))
+		}
+
+		return index(latest)
+	}
+
+	if err := indexHead(); err != nil {
+		return err
+	}
+
+	heights, err := i.client.SubscribeNewBlocks(ctx)
+	if err != nil {
+		log.Printf("failed to subscribe to new blocks on %s, polling every %s. err: %v", i.client.ChainID(), pollInterval, err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("stop following %s at height %d", i.client.ChainID(), nextBlock-1)
+			return nil
+
+		case height, ok := <-heights:
+			if !ok {
+				if ctx.Err() == nil {
+					log.Printf("new blocks subscription on %s closed, polling every %s", i.client.ChainID(), pollInterval)
+				}
+
+				heights = nil
+				continue
+			}
+
+			if err := index(height); err != nil {
+				return err
+			}
+
+		case <-ticker.C:
+			if err := indexHead(); err != nil {
+				return err
+			}
+		}
+	}
+}
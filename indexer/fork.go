@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// maxForkRollbacks is the number of rollbacks done by a single Parse before
+// giving up, it protects from nodes returning inconsistent blocks.
+const maxForkRollbacks = 3
+
+// maxReorgDepth is the number of blocks below the fork height searched for
+// the fork point.
+const maxReorgDepth = 100
+
+// checkContinuity verifies that block matches the blocks already stored: the
+// stored block at the same height must have the same hash and the stored parent
+// must be the one referenced by LastBlockID.
+func (i *Indexer) checkContinuity(block *coretypes.ResultBlock) error {
+	blockRepo := repository.NewBlockRepository()
+
+	chainID := block.Block.ChainID
+	height := block.Block.Height
+	hash := block.BlockID.Hash.String()
+
+	stored := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &height})
+	if stored.Height != 0 && stored.Hash != hash {
+		return newError(ErrFork, height, "stored block hash %s, node returned %s", stored.Hash, hash)
+	}
+
+	parentHeight := height - 1
+	parentHash := block.Block.LastBlockID.Hash.String()
+
+	parent := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &parentHeight})
+	if parent.Height != 0 && parent.Hash != parentHash {
+		return newError(ErrFork, parentHeight, "stored block hash %s, block %d points to %s", parent.Hash, height, parentHash)
+	}
+
+	return nil
+}
+
+// Rollback finds the highest stored block, starting from height, still
+// matching the chain, removes all the data indexed or derived above it and
+// rewinds the sync checkpoints. It returns the fork point, indexing must
+// restart from the following block.
+//
+// The candles and the leaderboards are rebuilt from their checkpoints by the
+// next sync, as well as the assets of the pools created up to the fork point,
+// which are queried again at the fork point. The accounts first seen after the
+// fork point are rebuilt here.
+func (i *Indexer) Rollback(height int64) (int64, error) {
+	forkPoint, err := i.findForkPoint(height)
+	if err != nil {
+		return 0, err
+	}
+
+	chainID := i.client.ChainID()
+	log.Printf("fork detected on %s, rolling back to height %d", chainID, forkPoint)
+
+	blockRepo := repository.NewBlockRepository()
+	forkTime := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &forkPoint}).Time
+
+	// blocks are removed last, so that an interrupted rollback is detected
+	// again on the next run
+	steps := []struct {
+		name     string
+		rollback func(chainID string, height int64) (int64, error)
+	}{
+		{"transactions", repository.NewTransactionRepository().DeleteAboveHeight},
+		{"messages", model.DeleteMsgsAboveHeight},
+		{"accounts", func(string, int64) (int64, error) { return model.RebuildAccountsAfter(forkTime) }},
+		{"swaps", repository.NewSwapRepository().DeleteAboveHeight},
+		{"liquidity events", repository.NewLiquidityRepository().DeleteAboveHeight},
+		{"liquidity snapshots", repository.NewHistoricalLiquidityRepository().DeleteAboveHeight},
+		{"pools", repository.NewPoolRepository().DeleteAboveHeight},
+		{"pool stats", repository.NewPoolStatsRepository().DeleteAboveHeight},
+		{"incentives", repository.NewIncentiveRepository().DeleteAboveHeight},
+		{"fantokens", repository.NewFantokenRepository().DeleteAboveHeight},
+		{"merkledrop claims", model.UnclaimMerkledropProofsAboveHeight},
+		{"merkledrop proofs", model.DeleteMerkledropProofsAboveHeight},
+		{"merkledrops", model.DeleteMerkledropsAboveHeight},
+		{"skipped blocks", repository.NewSkippedBlockRepository().DeleteAboveHeight},
+		{"blocks", blockRepo.DeleteAboveHeight},
+	}
+
+	for _, s := range steps {
+		n, err := s.rollback(chainID, forkPoint)
+		if err != nil {
+			return 0, newError(ErrStorage, forkPoint, "failed to rollback %s: %w", s.name, err)
+		}
+
+		log.Printf("rolled back %d %s above height %d", n, s.name, forkPoint)
+	}
+
+	if _, err := repository.NewCheckpointRepository().Rewind(chainID, forkPoint); err != nil {
+		return 0, newError(ErrStorage, forkPoint, "failed to rewind checkpoints: %w", err)
+	}
+
+	return forkPoint, nil
+}
+
+// findForkPoint returns the highest stored block, at most maxReorgDepth blocks
+// below height, matching the block of the node. A fork deeper than that, or
+// below the first stored block, cannot be rolled back and needs a reindex.
+func (i *Indexer) findForkPoint(height int64) (int64, error) {
+	blockRepo := repository.NewBlockRepository()
+	chainID := i.client.ChainID()
+
+	lowest := height - maxReorgDepth
+	if lowest < 1 {
+		lowest = 1
+	}
+
+	for h := height; h >= lowest; h-- {
+		h := h
+
+		stored := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &h})
+		if stored.Height == 0 {
+			continue
+		}
+
+		block, err := i.client.QueryBlock(context.Background(), &h)
+		if err != nil {
+			return 0, newError(ErrTransient, h, "failed to get block: %w", err)
+		}
+
+		if block.BlockID.Hash.String() == stored.Hash {
+			return h, nil
+		}
+	}
+
+	return 0, newError(ErrStorage, height, "no stored block matches the chain between heights %d and %d, the chain must be reindexed", lowest, height)
+}
+
+func (i *Indexer) parseWithRollback(fromBlock, toBlock int64, parse func(fromBlock, toBlock int64) error) error {
+	for rollbacks := 0; ; rollbacks++ {
+		err := parse(fromBlock, toBlock)
+		if !IsFork(err) || rollbacks >= maxForkRollbacks {
+			return err
+		}
+
+		var idxErr *Error
+		errors.As(err, &idxErr)
+
+		forkPoint, err := i.Rollback(idxErr.Height)
+		if err != nil {
+			return err
+		}
+
+		if forkPoint+1 < fromBlock {
+			fromBlock = forkPoint + 1
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
 	"github.com/angelorc/sinfonia-go/mongo/repository"
 	types2 "github.com/angelorc/sinfonia-go/mongo/types"
 	"golang.org/x/exp/slices"
@@ -44,6 +46,9 @@ type Indexer struct {
 	client     types.ClientI
 	modules    *IndexModules
 	concurrent int
+
+	mutex   sync.Mutex
+	skipped []int64
 }
 
 func NewIndexer(client types.ClientI, modules *IndexModules, concurrent int) *Indexer {
@@ -54,7 +59,16 @@ func NewIndexer(client types.ClientI, modules *IndexModules, concurrent int) *In
 	}
 }
 
-func (i *Indexer) Parse(fromBlock, toBlock int64) {
+// Parse indexes the blocks from fromBlock to toBlock. Blocks failing with a
+// transient error are retried, blocks that cannot be decoded are skipped and
+// recorded (see SkippedBlocks), any other error stops the parsing and is
+// returned as *Error. When a fork is detected the data above the fork point is
+// rolled back and the blocks are indexed again.
+func (i *Indexer) Parse(fromBlock, toBlock int64) error {
+	return i.parseWithRollback(fromBlock, toBlock, i.parse)
+}
+
+func (i *Indexer) parse(fromBlock, toBlock int64) error {
 	blocks := make([]int64, 0)
 
 	for i := fromBlock; i <= toBlock; i++ {
@@ -62,19 +76,70 @@ func (i *Indexer) Parse(fromBlock, toBlock int64) {
 	}
 
 	if i.modules.Blocks {
-		if err := i.parseBlocks(blocks, i.concurrent, i.IndexTransactions); err != nil {
-			log.Fatalf("failed to index blocks. err: %v", err)
+		if err := i.parseBlocks(blocks, i.concurrent, i.IndexTransactions, RtyAttNum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SkippedBlocks returns the heights skipped because of decode errors.
+func (i *Indexer) SkippedBlocks() []int64 {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return append([]int64(nil), i.skipped...)
+}
+
+// RetrySkipped indexes again the blocks skipped by the previous runs, the
+// blocks indexed are removed from the skipped blocks and the others are
+// skipped again.
+func (i *Indexer) RetrySkipped() error {
+	skippedRepo := repository.NewSkippedBlockRepository()
+	chainID := i.client.ChainID()
+
+	blocks, err := skippedRepo.Find(chainID)
+	if err != nil {
+		return newError(ErrStorage, 0, "failed to read skipped blocks: %w", err)
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	heights := make([]int64, len(blocks))
+	for j, block := range blocks {
+		heights[j] = block.Height
+	}
+	log.Printf("retrying %d skipped blocks on %s: %v", len(heights), chainID, heights)
+
+	before := len(i.SkippedBlocks())
+	if err := i.parseBlocks(heights, i.concurrent, i.IndexTransactions, RtyAttNum); err != nil {
+		return err
+	}
+
+	still := i.SkippedBlocks()[before:]
+	for _, height := range heights {
+		if slices.Contains(still, height) {
+			continue
+		}
+
+		if err := skippedRepo.Delete(chainID, height); err != nil {
+			return newError(ErrStorage, height, "failed to delete skipped block: %w", err)
 		}
 	}
+
+	return nil
 }
 
-func (i *Indexer) parseBlocks(blocks []int64, concurrent int, cb func(height int64) error) error {
+func (i *Indexer) parseBlocks(blocks []int64, concurrent int, cb func(height int64) error, rounds uint) error {
 	fmt.Println("starting block queries for", i.client.ChainID())
 
 	var (
 		eg           errgroup.Group
 		mutex        sync.Mutex
 		failedBlocks = make([]int64, 0)
+		lastErr      error
 		sem          = make(chan struct{}, concurrent)
 	)
 
@@ -83,17 +148,30 @@ func (i *Indexer) parseBlocks(blocks []int64, concurrent int, cb func(height int
 		sem <- struct{}{}
 
 		eg.Go(func() error {
-			if err := cb(height); err != nil {
-				if strings.Contains(err.Error(), "wrong ID: no ID") {
-					mutex.Lock()
-					failedBlocks = append(failedBlocks, height)
-					mutex.Unlock()
-				} else {
-					return fmt.Errorf("[height %d] - failed to get block. err: %s", height, err.Error())
+			defer func() { <-sem }()
+
+			err := cb(height)
+			switch {
+			case err == nil:
+			case IsTransient(err):
+				mutex.Lock()
+				failedBlocks = append(failedBlocks, height)
+				lastErr = err
+				mutex.Unlock()
+			case IsDecode(err):
+				log.Printf("skipping block: %v", err)
+
+				if err := repository.NewSkippedBlockRepository().Save(i.client.ChainID(), height, err.Error()); err != nil {
+					return newError(ErrStorage, height, "failed to record skipped block: %w", err)
 				}
+
+				i.mutex.Lock()
+				i.skipped = append(i.skipped, height)
+				i.mutex.Unlock()
+			default:
+				return err
 			}
 
-			<-sem
 			return nil
 		})
 	}
@@ -103,7 +181,11 @@ func (i *Indexer) parseBlocks(blocks []int64, concurrent int, cb func(height int
 	}
 
 	if len(failedBlocks) > 0 {
-		return i.parseBlocks(failedBlocks, concurrent, cb)
+		if rounds <= 1 {
+			return lastErr
+		}
+
+		return i.parseBlocks(failedBlocks, concurrent, cb, rounds-1)
 	}
 
 	return nil
@@ -122,14 +204,27 @@ func (i *Indexer) IndexTransactions(height int64) error {
 
 			return nil
 		}, RtyAtt, RtyDel, RtyErr, retry.DelayType(retry.BackOffDelay), retry.OnRetry(func(n uint, err error) {
-			log.Fatalf("retry: attempt %d, height %d, err: %v", n, height, err)
+			log.Printf("retry: attempt %d, height %d, err: %v", n, height, err)
 		})); err != nil {
-			return err
+			return newError(ErrTransient, height, "failed to get block: %w", err)
 		}
 	}
 
 	if block == nil {
-		return fmt.Errorf("block not found")
+		return newError(ErrTransient, height, "block not found")
+	}
+
+	if err := i.checkContinuity(block); err != nil {
+		return err
+	}
+
+	// the txs are decoded before anything is written, so that a skipped
+	// block leaves no data behind
+	var txs []*modelv2.TransactionCreateReq
+	if i.modules.Transactions {
+		if txs, err = i.queryTxs(block.Block.ChainID, height, block.Block.Data.Txs, block.Block.Time); err != nil {
+			return err
+		}
 	}
 
 	blockRepo := repository.NewBlockRepository()
@@ -142,12 +237,14 @@ func (i *Indexer) IndexTransactions(height int64) error {
 
 	if err != nil {
 		if !strings.Contains(err.Error(), "E11000 duplicate key error") {
-			log.Fatalf("[Height %d] - Failed to write block to db. Err: %s", height, err.Error())
+			return newError(ErrStorage, height, "failed to write block to db: %w", err)
 		}
+	} else {
+		pubsub.Publish(pubsub.TopicBlocks, blockDB)
 	}
 
-	if i.modules.Transactions {
-		i.parseTxs(blockDB.ID, block.Block.ChainID, height, block.Block.Data.Txs, block.Block.Time)
+	if err := i.storeTxs(height, txs); err != nil {
+		return err
 	}
 
 	/*if i.modules.BlockResults {
@@ -159,11 +256,31 @@ func (i *Indexer) IndexTransactions(height int64) error {
 	return nil
 }
 
-func (i *Indexer) parseTxs(blockID primitive.ObjectID, chainID string, height int64, txs tmtypes.Txs, time time.Time) {
+// queryTxs queries and decodes the successful txs of a block.
+func (i *Indexer) queryTxs(chainID string, height int64, txs tmtypes.Txs, time time.Time) ([]*modelv2.TransactionCreateReq, error) {
+	creates := make([]*modelv2.TransactionCreateReq, 0, len(txs))
+
 	for index, tx := range txs {
 		txTx, sdkTxRes, err := i.client.QueryTx(context.Background(), tx.Hash())
+		if err != nil && !errors.Is(err, types.ErrDecodeTx) {
+			err = retry.Do(func() error {
+				txTx, sdkTxRes, err = i.client.QueryTx(context.Background(), tx.Hash())
+				if errors.Is(err, types.ErrDecodeTx) {
+					return retry.Unrecoverable(err)
+				}
+
+				return err
+			}, RtyAtt, RtyDel, RtyErr, retry.DelayType(retry.BackOffDelay), retry.OnRetry(func(n uint, err error) {
+				log.Printf("retry tx: attempt %d, height %d, err: %v", n, height, err)
+			}))
+		}
+
 		if err != nil {
-			log.Fatalf("[Height %d] {%d/%d txs} - Failed to query tx results. Err: %s \n", height, index+1, len(txs), err.Error())
+			if errors.Is(err, types.ErrDecodeTx) {
+				return nil, newError(ErrDecode, height, "{%d/%d txs} failed to decode tx: %w", index+1, len(txs), err)
+			}
+
+			return nil, newError(ErrTransient, height, "{%d/%d txs} failed to query tx results: %w", index+1, len(txs), err)
 		}
 
 		if sdkTxRes.Code > 0 {
@@ -206,10 +323,14 @@ func (i *Indexer) parseTxs(blockID primitive.ObjectID, chainID string, height in
 		abciLogs := ConvertABCIMessageLogs(sdkTxRes.Logs)
 
 		allowedEvtTypes := []string{
+			"message",
 			"token_swapped",
 			"pool_created",
 			"pool_joined",
 			"pool_exited",
+			// the pool shares minted by the joins and burned by the exits
+			"coinbase",
+			"burn",
 			"epoch_start",
 			"epoch_end",
 			"distribution",
@@ -245,9 +366,8 @@ func (i *Indexer) parseTxs(blockID primitive.ObjectID, chainID string, height in
 		hashStr := hex.EncodeToString(tx.Hash())
 
 		fee := ConvertCoins(txTx.GetFee())
-		txRepo := repository.NewTransactionRepository()
 
-		_, err = txRepo.Create(&modelv2.TransactionCreateReq{
+		creates = append(creates, &modelv2.TransactionCreateReq{
 			ChainID:   chainID,
 			Height:    height,
 			Hash:      hashStr,
@@ -259,23 +379,35 @@ func (i *Indexer) parseTxs(blockID primitive.ObjectID, chainID string, height in
 			Time:      time,
 		})
 
-		if err != nil {
-			if !strings.Contains(err.Error(), "E11000 duplicate key error") {
-				log.Fatalf("[Height %d] - Failed to write tx to db. Err: %s", height, err.Error())
-			}
-		}
-
-		log.Printf("[Height %d] {%d/%d txs} - Successfuly wrote tx to db with %d msgs.", height, index+1, len(txs), len(txTx.GetMsgs()))
-
 		/*for msgIndex, msg := range txTx.GetMsgs() {
 			i.HandleMsg(txID, chainID, msg, msgIndex, height, time)
 		}
 
 		i.HandleLogs(sdkTxRes.Logs, txTx.GetMsgs(), height, tx.Hash(), time)*/
 	}
+
+	return creates, nil
 }
 
-func (i *Indexer) HandleMsg(txID primitive.ObjectID, chainID string, msg sdk.Msg, msgIndex int, height int64, time time.Time) {
+// storeTxs writes the decoded txs of a block, the txs already stored are
+// left.
+func (i *Indexer) storeTxs(height int64, txs []*modelv2.TransactionCreateReq) error {
+	txRepo := repository.NewTransactionRepository()
+
+	for index, tx := range txs {
+		if _, err := txRepo.Create(tx); err != nil {
+			if !strings.Contains(err.Error(), "E11000 duplicate key error") {
+				return newError(ErrStorage, height, "failed to write tx to db: %w", err)
+			}
+		}
+
+		log.Printf("[Height %d] {%d/%d txs} - Successfuly wrote tx to db.", height, index+1, len(txs))
+	}
+
+	return nil
+}
+
+func (i *Indexer) HandleMsg(txID primitive.ObjectID, chainID string, msg sdk.Msg, msgIndex int, height int64, time time.Time) error {
 	signer := i.client.MustEncodeAccAddr(msg.GetSigners()[0])
 
 	marshaler := jsonpb.Marshaler{
@@ -288,13 +420,13 @@ func (i *Indexer) HandleMsg(txID primitive.ObjectID, chainID string, msg sdk.Msg
 	msgType := sdk.MsgTypeURL(msg)
 	msgValueStr, err := marshaler.MarshalToString(msg)
 	if err != nil {
-		log.Fatalf("Failed to marshal msg - index (%d), height (%d), err: %s", msgIndex, height, err.Error())
+		return newError(ErrDecode, height, "failed to marshal msg %d: %w", msgIndex, err)
 	}
 
 	var msgValue scalar.JSON
 	err = json.Unmarshal([]byte(msgValueStr), &msgValue)
 	if err != nil {
-		log.Fatalf("Failed to marshaljson msg  - index (%d), height (%d), err: %s", msgIndex, height, err.Error())
+		return newError(ErrDecode, height, "failed to unmarshal json msg %d: %w", msgIndex, err)
 	}
 
 	data := &model.MessageCreate{
@@ -309,7 +441,7 @@ func (i *Indexer) HandleMsg(txID primitive.ObjectID, chainID string, msg sdk.Msg
 	}
 	err = model.InsertMsg(data)
 	if err != nil {
-		log.Fatalf("Failed to insert MsgSend - index (%d), height (%d), err: %s", msgIndex, height, err.Error())
+		return newError(ErrStorage, height, "failed to insert msg %d: %w", msgIndex, err)
 	}
 
 	/*switch m := msg.(type) {
@@ -372,6 +504,7 @@ func (i *Indexer) HandleMsg(txID primitive.ObjectID, chainID string, msg sdk.Msg
 		log.Fatalf("Unknown msg type: %s", sdk.MsgTypeURL(msg))
 	}*/
 
+	return nil
 }
 
 func (i *Indexer) HandleLogs(logs sdk.ABCIMessageLogs, msgs []sdk.Msg, height int64, hash []byte, timestamp time.Time) {
@@ -391,9 +524,9 @@ func (i *Indexer) parseBlockResults(height int64, ts time.Time) error {
 
 			return nil
 		}, RtyAtt, RtyDel, RtyErr, retry.DelayType(retry.BackOffDelay), retry.OnRetry(func(n uint, err error) {
-			log.Fatalf("retry block_results: attempt %d, height %d, err: %v", n, height, err)
+			log.Printf("retry block_results: attempt %d, height %d, err: %v", n, height, err)
 		})); err != nil {
-			return err
+			return newError(ErrTransient, height, "failed to get block results: %w", err)
 		}
 	}
 
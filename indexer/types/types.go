@@ -2,13 +2,19 @@ package types
 
 import (
 	"context"
+	"errors"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/tx"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 )
 
+// ErrDecodeTx is wrapped by the clients when a tx returned by the node cannot be decoded.
+var ErrDecodeTx = errors.New("failed to decode tx")
+
 type ClientI interface {
 	ChainID() string
+	LatestBlockHeight(ctx context.Context) (int64, error)
+	SubscribeNewBlocks(ctx context.Context) (<-chan int64, error)
 	QueryBlock(ctx context.Context, height *int64) (*coretypes.ResultBlock, error)
 	QueryBlockResults(ctx context.Context, height *int64) (*coretypes.ResultBlockResults, error)
 	QueryTx(ctx context.Context, hash []byte) (*tx.Tx, *sdk.TxResponse, error)
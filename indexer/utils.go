@@ -7,10 +7,7 @@ import (
 )
 
 func ConvertCoin(coin sdk.Coin) modelv2.Coin {
-	return modelv2.Coin{
-		Amount: coin.Amount.ToDec().MustFloat64(),
-		Denom:  coin.Denom,
-	}
+	return modelv2.MustNewCoin(coin.Denom, coin.Amount.String())
 }
 
 func ConvertCoins(coins sdk.Coins) *[]modelv2.Coin {
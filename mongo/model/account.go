@@ -3,12 +3,13 @@ package model
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
 	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
 	"time"
 )
 
@@ -26,6 +27,7 @@ const DB_REF_NAME__ACCOUNT = "default"
 type Account struct {
 	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	Address      string             `json:"address" bson:"address"`
+	AddressKey   string             `json:"address_key" bson:"address_key"`
 	ValueSwapped string             `json:"value_swapped" bson:"value_swapped"`
 	FeesPaid     string             `json:"fees_paid" bson:"fees_paid"`
 	TotalTxs     string             `json:"total_txs" bson:"total_txs"`
@@ -57,9 +59,10 @@ type AccountWhere struct {
 // Write
 
 type AccountCreate struct {
-	ID        *primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	Address   string              `json:"address" bson:"address"`
-	FirstSeen time.Time           `json:"first_seen" bson:"first_seen"`
+	ID         *primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Address    string              `json:"address" bson:"address"`
+	AddressKey string              `json:"address_key" bson:"address_key"`
+	FirstSeen  time.Time           `json:"first_seen" bson:"first_seen"`
 }
 
 /**
@@ -174,9 +177,13 @@ func (m *Account) Create(data *AccountCreate) error {
 
 func EnsureAccount(acc string, firstSeen time.Time) error {
 	item := Account{}
+	// the key is empty for an address that is not bech32
+	key, _ := utility.AddressKey(acc)
+
 	data := AccountCreate{
-		Address:   acc,
-		FirstSeen: firstSeen,
+		Address:    acc,
+		AddressKey: key,
+		FirstSeen:  firstSeen,
 	}
 
 	if err := utility.ValidateStruct(data); err != nil {
@@ -190,16 +197,16 @@ func EnsureAccount(acc string, firstSeen time.Time) error {
 	return nil
 }
 
-func SyncAccounts() error {
-	lasBlock := GetLastHeight("osmosis-1")
+func SyncAccounts(chainID string, genesisHeight int64) error {
+	lasBlock := GetLastHeight(chainID)
 
 	// get last block synced from account
-	sync := new(Sync)
-	sync.One()
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainID, "accounts")
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Accounts = int64(0)
+	syncedBlock := checkpoint.Height
+	if syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
 	}
 
 	// collection
@@ -212,7 +219,7 @@ func SyncAccounts() error {
 		{
 			"$match": bson.M{
 				"height": bson.M{
-					"$gt":  sync.Accounts,
+					"$gt":  syncedBlock,
 					"$lte": lasBlock,
 				},
 			},
@@ -258,12 +265,54 @@ func SyncAccounts() error {
 	}
 
 	// update sync with last synced height
-	sync.Accounts = lasBlock
-	if err := sync.Save(); err != nil {
+	if err := checkpointRepo.Save(checkpoint, lasBlock); err != nil {
 		return err
 	}
 
-	fmt.Println(fmt.Sprintf("%d accounts synced to block %d ", len(accounts), sync.Accounts))
+	log.Printf("%d accounts synced to block %d", len(accounts), checkpoint.Height)
 
 	return nil
 }
+
+// RebuildAccountsAfter removes the accounts first seen after t and ensures
+// them again from the stored messages, so that the accounts only seen in
+// rolled back blocks are removed. It returns the number of accounts deleted
+// before ensuring them again.
+func RebuildAccountsAfter(t time.Time) (int64, error) {
+	accounts := db.GetCollection(DB_COLLECTION_NAME__ACCOUNT, DB_REF_NAME__ACCOUNT)
+	messages := db.GetCollection(DB_COLLECTION_NAME__MESSAGE, DB_REF_NAME__MESSAGE)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	res, err := accounts.DeleteMany(ctx, bson.M{"first_seen": bson.M{"$gt": t}})
+	if err != nil {
+		return 0, err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"time": bson.M{"$gt": t}}},
+		{"$sort": bson.M{"time": 1}},
+		{"$group": bson.M{"_id": "$signer", "first_seen": bson.M{"$first": "$time"}}},
+	}
+
+	cursor, err := messages.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var seen []struct {
+		Signer    string    `bson:"_id"`
+		FirstSeen time.Time `bson:"first_seen"`
+	}
+	if err := cursor.All(ctx, &seen); err != nil {
+		return 0, err
+	}
+
+	for _, a := range seen {
+		if err := EnsureAccount(a.Signer, a.FirstSeen); err != nil {
+			return 0, err
+		}
+	}
+
+	return res.DeletedCount, nil
+}
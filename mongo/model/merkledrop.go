@@ -3,12 +3,14 @@ package model
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/mongo/types"
 	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
 )
 
@@ -48,12 +50,6 @@ type Merkledrop struct {
 	Time time.Time `json:"time,omitempty" bson:"time,omitempty" validate:"required"`
 }
 
-/**
- * ENUM
- */
-
-type MerkledropOrderByENUM string
-
 /**
  * DTO
  */
@@ -137,36 +133,29 @@ func (m *Merkledrop) One(filter *MerkledropWhere) error {
 	return nil
 }
 
-func (m *Merkledrop) List(filter *MerkledropWhere, orderBy *MerkledropOrderByENUM, skip *int, limit *int, customQuery *bson.M) ([]*Merkledrop, error) {
+// Find returns a page of the merkledrops matching filter, and with an _id in
+// in when given.
+func (m *Merkledrop) Find(filter *MerkledropWhere, in []*primitive.ObjectID, pagination *types.PaginationReq) ([]*Merkledrop, error) {
 	var items []*Merkledrop
-	orderByKey := "time"
-	orderByValue := -1
 
 	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP, DB_REF_NAME__MERKLEDROP)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	options := options.Find()
-	if limit != nil {
-		options.SetLimit(int64(*limit))
-	}
-	if skip != nil {
-		options.SetSkip(int64(*skip))
-	}
-	if orderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(string(*orderBy))
-	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
-
-	var queryFilter interface{}
+	var queryFilter interface{} = bson.M{}
 	if filter != nil {
 		queryFilter = filter
 	}
-	if !utility.IsZeroVal(customQuery) {
-		queryFilter = customQuery
+	if in != nil {
+		queryFilter = bson.M{"$and": bson.A{queryFilter, bson.M{"_id": bson.M{"$in": in}}}}
 	}
 
-	cursor, err := collection.Find(ctx, &queryFilter, options)
+	query, options, err := repository.Paginate(queryFilter, pagination, "height", -1)
+	if err != nil {
+		return items, err
+	}
+
+	cursor, err := collection.Find(ctx, query, options)
 	if err != nil {
 		return items, err
 	}
@@ -260,3 +249,45 @@ func (m *Merkledrop) Update(id int64, data *MerkledropUpdate) error {
 
 	return nil
 }
+
+/**
+ * INDEXER API
+ */
+
+// DeleteMerkledropsAboveHeight removes the merkledrops of chainID created
+// above height, their proofs are removed by DeleteMerkledropProofsAboveHeight.
+func DeleteMerkledropsAboveHeight(chainID string, height int64) (int64, error) {
+	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP, DB_REF_NAME__MERKLEDROP)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	res, err := collection.DeleteMany(ctx, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// merkledropIDs returns the ids of the merkledrops matching filter.
+func merkledropIDs(filter bson.M) ([]int64, error) {
+	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP, DB_REF_NAME__MERKLEDROP)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	values, err := collection.Distinct(ctx, "merkledrop_id", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		id, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid merkledrop_id %v", v)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -2,8 +2,11 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/mongo/types"
 	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,6 +29,10 @@ const DB_REF_NAME__MERKLEDROP_PROOF = "default"
 
 var SEARCH_FILEDS__MERKLEDROP_PROOF = []string{"merkledrop_id"}
 
+// ErrDuplicateProof is returned by StoreMany when a proof of the same
+// merkledrop and address is already stored.
+var ErrDuplicateProof = errors.New("duplicate record")
+
 /**
  * MODEL
  */
@@ -36,9 +43,13 @@ type MerkledropProof struct {
 	MerkledropID int64    `json:"merkledrop_id" bson:"merkledrop_id"`
 	Index        int64    `json:"index" bson:"index"`
 	Address      string   `json:"address" bson:"address"`
+	AddressKey   string   `json:"address_key" bson:"address_key"`
 	Amount       int64    `json:"amount" bson:"amount"`
 	Proofs       []string `json:"proofs" bson:"proofs"`
 	Claimed      bool     `json:"claimed" bson:"claimed"`
+	// ClaimedHeight and ClaimedAt are the block of the claim
+	ClaimedHeight int64      `json:"claimed_height,omitempty" bson:"claimed_height,omitempty"`
+	ClaimedAt     *time.Time `json:"claimed_at,omitempty" bson:"claimed_at,omitempty"`
 
 	CreatedAt time.Time `json:"created_at,omitempty" bson:"created_at,omitempty" validate:"required"`
 }
@@ -64,9 +75,11 @@ type MerkledropProofWhere struct {
 
 	MerkledropID *int64    `json:"merkledrop_id,omitempty" bson:"merkledrop_id,omitempty"`
 	Address      *string   `json:"address,omitempty" bson:"address,omitempty"`
+	AddressKey   *string   `json:"address_key,omitempty" bson:"address_key,omitempty"`
 	Index        *int64    `json:"index,omitempty" bson:"index,omitempty"`
 	Amount       *int64    `json:"amount,omitempty" bson:"amount,omitempty"`
 	Proofs       *[]string `json:"proofs,omitempty" bson:"proofs,omitempty"`
+	Claimed      *bool     `json:"claimed,omitempty" bson:"claimed,omitempty"`
 
 	OR []bson.M `json:"$or,omitempty" bson:"$or,omitempty"`
 }
@@ -77,6 +90,7 @@ type MerkledropProofCreate struct {
 	ID           *primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	MerkledropID int64               `json:"merkledrop_id" bson:"merkledrop_id,omitempty"`
 	Address      string              `json:"address" bson:"address,omitempty"`
+	AddressKey   string              `json:"address_key" bson:"address_key,omitempty"`
 	Index        int64               `json:"index" bson:"index"`
 	Amount       int64               `json:"amount" bson:"amount,omitempty"`
 	Proofs       []string            `json:"proofs" bson:"proofs,omitempty"`
@@ -88,6 +102,9 @@ type MerkledropProofClaim struct {
 	MerkledropID int64  `json:"merkledrop_id" bson:"merkledrop_id,omitempty" validate:"required"`
 	Address      string `json:"address" bson:"address,omitempty" validate:"required"`
 	Index        int64  `json:"index" bson:"index" validate:"required"`
+
+	Height int64     `json:"height" bson:"-"`
+	Time   time.Time `json:"time" bson:"-"`
 }
 
 /**
@@ -147,6 +164,40 @@ func (m *MerkledropProof) List(filter *MerkledropProofWhere, orderBy *Merkledrop
 	return items, nil
 }
 
+// Find returns a page of the proofs matching filter, and with an _id in in
+// when given.
+func (m *MerkledropProof) Find(filter *MerkledropProofWhere, in []*primitive.ObjectID, pagination *types.PaginationReq) ([]*MerkledropProof, error) {
+	var items []*MerkledropProof
+
+	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP_PROOF, DB_REF_NAME__MERKLEDROP_PROOF)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var queryFilter interface{} = bson.M{}
+	if filter != nil {
+		queryFilter = filter
+	}
+	if in != nil {
+		queryFilter = bson.M{"$and": bson.A{queryFilter, bson.M{"_id": bson.M{"$in": in}}}}
+	}
+
+	query, options, err := repository.Paginate(queryFilter, pagination, "created_at", -1)
+	if err != nil {
+		return items, err
+	}
+
+	cursor, err := collection.Find(ctx, query, options)
+	if err != nil {
+		return items, err
+	}
+	err = cursor.All(ctx, &items)
+	if err != nil {
+		return items, err
+	}
+
+	return items, nil
+}
+
 func (m *MerkledropProof) Count(filter *MerkledropProofWhere) (int, error) {
 	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP_PROOF, DB_REF_NAME__MERKLEDROP_PROOF)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -176,14 +227,15 @@ func (m *MerkledropProof) StoreMany(data []MerkledropProofCreate) error {
 	items := make([]interface{}, len(data))
 
 	for i, proof := range data {
+		proof.AddressKey, _ = utility.AddressKey(proof.Address)
 		items[i] = proof
 	}
 
 	// operation
 	_, err := collection.InsertMany(ctx, items)
 	if err != nil {
-		if strings.HasPrefix(err.Error(), "bulk write exception") {
-			return fmt.Errorf("duplicate record")
+		if mongo.IsDuplicateKeyError(err) || strings.HasPrefix(err.Error(), "bulk write exception") {
+			return ErrDuplicateProof
 		}
 
 		return err
@@ -216,7 +268,13 @@ func (m *MerkledropProof) SetClaimed(data MerkledropProofClaim) error {
 	}
 
 	// update
-	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"claimed": true}})
+	update := bson.M{"claimed": true}
+	if data.Height > 0 {
+		update["claimed_height"] = data.Height
+		update["claimed_at"] = data.Time
+	}
+
+	_, err := collection.UpdateOne(ctx, filter, bson.M{"$set": update})
 	if err != nil {
 		return err
 	}
@@ -227,24 +285,93 @@ func (m *MerkledropProof) SetClaimed(data MerkledropProofClaim) error {
 }
 
 func (m *MerkledropProof) CreateIndexes() error {
+	// an address has one proof per merkledrop, the uploads of the same
+	// merkledrop racing each other fail on it
 	index := mongo.IndexModel{
 		Keys: bson.D{
 			{"merkledrop_id", 1},
 			{"address", 1},
-			{"index", 1},
 		},
 		Options: options.Index().SetUnique(true),
 	}
 
+	keyIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "address_key", Value: 1}},
+		Options: options.Index().SetUnique(false),
+	}
+
+	claimedIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "claimed", Value: 1}, {Key: "claimed_at", Value: 1}},
+		Options: options.Index().SetUnique(false),
+	}
+
+	// the paginated lists
+	createdIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetUnique(false),
+	}
+
 	// collection
 	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP_PROOF, DB_REF_NAME__MERKLEDROP_PROOF)
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	_, err := collection.Indexes().CreateOne(ctx, index)
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{index, keyIndex, claimedIndex, createdIndex})
 	if err != nil {
 		return fmt.Errorf("error while creting indexes on merkledrop_proofs: %v", err)
 	}
 
 	return nil
 }
+
+/**
+ * INDEXER API
+ */
+
+// DeleteMerkledropProofsAboveHeight removes the proofs of the merkledrops of
+// chainID created above height, a merkledrop created again after a fork may
+// have a different root.
+func DeleteMerkledropProofsAboveHeight(chainID string, height int64) (int64, error) {
+	ids, err := merkledropIDs(bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP_PROOF, DB_REF_NAME__MERKLEDROP_PROOF)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	res, err := collection.DeleteMany(ctx, bson.M{"merkledrop_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// UnclaimMerkledropProofsAboveHeight resets the proofs of the merkledrops of
+// chainID claimed above height, the claims are synced again from the
+// messages.
+func UnclaimMerkledropProofsAboveHeight(chainID string, height int64) (int64, error) {
+	ids, err := merkledropIDs(bson.M{"chain_id": chainID})
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	collection := db.GetCollection(DB_COLLECTION_NAME__MERKLEDROP_PROOF, DB_REF_NAME__MERKLEDROP_PROOF)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	filter := bson.M{"merkledrop_id": bson.M{"$in": ids}, "claimed_height": bson.M{"$gt": height}}
+	update := bson.M{
+		"$set":   bson.M{"claimed": false},
+		"$unset": bson.M{"claimed_height": "", "claimed_at": ""},
+	}
+
+	res, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
@@ -209,6 +209,20 @@ func InsertMsg(data *MessageCreate) error {
 	return nil
 }
 
+// DeleteMsgsAboveHeight removes the messages of chainID indexed above height.
+func DeleteMsgsAboveHeight(chainID string, height int64) (int64, error) {
+	collection := db.GetCollection(DB_COLLECTION_NAME__MESSAGE, DB_REF_NAME__MESSAGE)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	res, err := collection.DeleteMany(ctx, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 // TxLogs struct
 type TxLogs struct {
 	Signer   string             `bson:"signer"`
@@ -21,6 +21,8 @@ const DB_REF_NAME__SYNC = "default"
  * MODEL
  */
 
+// Sync is the legacy cursor document shared by all the sync modules, it has been
+// replaced by the checkpoints and it is only read to import the old cursors.
 type Sync struct {
 	ID               primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	Accounts         int64              `json:"accounts" bson:"accounts"`
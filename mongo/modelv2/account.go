@@ -0,0 +1,65 @@
+package modelv2
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+type Account struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Address    string             `json:"address" bson:"address"`
+	AddressKey string             `json:"address_key" bson:"address_key"`
+	FirstSeen  time.Time          `json:"first_seen" bson:"first_seen"`
+}
+
+type AccountFilter struct {
+	Id         *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Address    *string             `json:"address,omitempty" bson:"address,omitempty"`
+	AddressKey *string             `json:"address_key,omitempty" bson:"address_key,omitempty"`
+}
+
+func (af *AccountFilter) Validate() error {
+	return nil
+}
+
+// AccountAddress is the address of an account on a chain, the addresses of
+// the same key differ only by the bech32 prefix.
+type AccountAddress struct {
+	ChainID string `json:"chain_id"`
+	Address string `json:"address"`
+}
+
+// AccountActivity is the activity of an account on all the indexed chains,
+// the values are in USD.
+type AccountActivity struct {
+	Swaps      AccountSwaps      `json:"swaps"`
+	Liquidity  AccountLiquidity  `json:"liquidity"`
+	Incentives AccountIncentives `json:"incentives"`
+
+	FantokensIssued  int64 `json:"fantokens_issued"`
+	MerkledropClaims int64 `json:"merkledrop_claims"`
+}
+
+// AccountSwaps sums the swaps of an account, the fees are valued at the USD
+// value of every swap. The times are nil when there are no swaps.
+type AccountSwaps struct {
+	Count     int64      `json:"count" bson:"count"`
+	Volume    float64    `json:"volume" bson:"volume"`
+	FeesPaid  float64    `json:"fees_paid" bson:"fees_paid"`
+	FirstTime *time.Time `json:"first_time" bson:"first_time"`
+	LastTime  *time.Time `json:"last_time" bson:"last_time"`
+}
+
+// AccountLiquidity sums the joins and the exits of an account.
+type AccountLiquidity struct {
+	Joins     int64   `json:"joins" bson:"joins"`
+	Exits     int64   `json:"exits" bson:"exits"`
+	Deposited float64 `json:"deposited" bson:"deposited"`
+	Withdrawn float64 `json:"withdrawn" bson:"withdrawn"`
+}
+
+// AccountIncentives sums the incentives received by an account.
+type AccountIncentives struct {
+	Count    int64   `json:"count" bson:"count"`
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
+}
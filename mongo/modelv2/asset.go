@@ -0,0 +1,106 @@
+package modelv2
+
+import (
+	"github.com/angelorc/sinfonia-go/utility"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"sort"
+)
+
+type Asset struct {
+	ID            primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID       string             `json:"chain_id" bson:"chain_id" validate:"required"`
+	Denom         string             `json:"denom" bson:"denom" validate:"required"`
+	Symbol        string             `json:"symbol" bson:"symbol" validate:"required"`
+	Decimals      int                `json:"decimals" bson:"decimals"`
+	CoingeckoID   string             `json:"coingecko_id" bson:"coingecko_id"`
+	QuotePriority int                `json:"quote_priority" bson:"quote_priority"`
+}
+
+func (a *Asset) Validate() error {
+	return utility.ValidateStruct(a)
+}
+
+type AssetFilter struct {
+	Id      *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Denom   *string             `json:"denom,omitempty" bson:"denom,omitempty"`
+}
+
+func (af *AssetFilter) Validate() error {
+	return nil
+}
+
+// AssetRegistry holds the tracked assets of a chain indexed by denom.
+type AssetRegistry struct {
+	assets map[string]*Asset
+}
+
+// NewAssetRegistry creates a registry from assets, when a denom is repeated
+// the last asset wins.
+func NewAssetRegistry(assets ...*Asset) *AssetRegistry {
+	registry := &AssetRegistry{assets: make(map[string]*Asset)}
+
+	for _, asset := range assets {
+		registry.assets[asset.Denom] = asset
+	}
+
+	return registry
+}
+
+func (r *AssetRegistry) Get(denom string) (*Asset, bool) {
+	asset, ok := r.assets[denom]
+	return asset, ok
+}
+
+// All returns the assets sorted by quote priority, the highest first.
+func (r *AssetRegistry) All() []*Asset {
+	assets := make([]*Asset, 0, len(r.assets))
+	for _, asset := range r.assets {
+		assets = append(assets, asset)
+	}
+
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].QuotePriority != assets[j].QuotePriority {
+			return assets[i].QuotePriority > assets[j].QuotePriority
+		}
+
+		return assets[i].Denom < assets[j].Denom
+	})
+
+	return assets
+}
+
+// QuoteIndex returns the index of the denom to use as quote asset in a pool,
+// -1 is returned when none of the denoms can be used as quote.
+func (r *AssetRegistry) QuoteIndex(denoms []string) int {
+	index := -1
+	priority := 0
+
+	for i, denom := range denoms {
+		asset, ok := r.assets[denom]
+		if !ok || asset.QuotePriority <= priority {
+			continue
+		}
+
+		index = i
+		priority = asset.QuotePriority
+	}
+
+	return index
+}
+
+// TrackPool tells if a pool with the given denoms is tracked, only two assets
+// pools with a quote asset are tracked. The pool is inverted when the quote
+// asset is the first one.
+func (r *AssetRegistry) TrackPool(denoms []string) (tracked bool, inverted bool) {
+	if len(denoms) != 2 {
+		return false, false
+	}
+
+	quote := r.QuoteIndex(denoms)
+	if quote < 0 {
+		return false, false
+	}
+
+	return true, quote == 0
+}
@@ -0,0 +1,42 @@
+package modelv2
+
+import "testing"
+
+func TestAssetRegistry_TrackPool(t *testing.T) {
+	const (
+		osmo = "uosmo"
+		atom = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+		btsg = "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452"
+		juno = "ibc/46B44899322F3CD854D2D46DEEF881958467CDD4B3B10086DA49296BBED94BED"
+	)
+
+	registry := NewAssetRegistry(
+		&Asset{Denom: osmo, Symbol: "OSMO", Decimals: 6, QuotePriority: 3},
+		&Asset{Denom: atom, Symbol: "ATOM", Decimals: 6, QuotePriority: 2},
+		&Asset{Denom: btsg, Symbol: "BTSG", Decimals: 6, QuotePriority: 1},
+	)
+
+	tests := []struct {
+		name     string
+		denoms   []string
+		tracked  bool
+		inverted bool
+	}{
+		{"atom/osmo", []string{atom, osmo}, true, false},
+		{"juno/osmo", []string{juno, osmo}, true, false},
+		{"atom/btsg", []string{atom, btsg}, true, true},
+		{"atom/juno", []string{atom, juno}, true, true},
+		{"juno/btsg", []string{juno, btsg}, true, false},
+		{"untracked", []string{juno, "uion"}, false, false},
+		{"three assets", []string{atom, btsg, osmo}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracked, inverted := registry.TrackPool(tt.denoms)
+			if tracked != tt.tracked || inverted != tt.inverted {
+				t.Errorf("TrackPool(%v) = %v, %v, want %v, %v", tt.denoms, tracked, inverted, tt.tracked, tt.inverted)
+			}
+		})
+	}
+}
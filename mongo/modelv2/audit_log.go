@@ -0,0 +1,43 @@
+package modelv2
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// AuditLog records a change made by an authenticated account: who made it,
+// with which role, what changed and when.
+type AuditLog struct {
+	ID primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	// Actor is the address signing the web3token of the change
+	Actor string `json:"actor" bson:"actor"`
+	Role  string `json:"role" bson:"role"`
+	// Action is the mutation making the change, eg: updateMerkledrop
+	Action string `json:"action" bson:"action"`
+	// Target is the kind of the changed resource and TargetID its id, eg:
+	// merkledrop 12
+	Target   string        `json:"target" bson:"target"`
+	TargetID string        `json:"target_id" bson:"target_id"`
+	Changes  []AuditChange `json:"changes" bson:"changes"`
+	// Status is the outcome of the change, Error the reason of a failure
+	Status AuditStatus `json:"status" bson:"status"`
+	Error  string      `json:"error,omitempty" bson:"error,omitempty"`
+	Time   time.Time   `json:"time" bson:"time"`
+}
+
+// AuditStatus is the outcome of an audited change, the change is recorded as
+// pending before anything is written.
+type AuditStatus string
+
+const (
+	AuditStatusPending   AuditStatus = "pending"
+	AuditStatusSucceeded AuditStatus = "succeeded"
+	AuditStatusFailed    AuditStatus = "failed"
+)
+
+// AuditChange is the change of a field, From is empty for the added values.
+type AuditChange struct {
+	Field string      `json:"field" bson:"field"`
+	From  interface{} `json:"from,omitempty" bson:"from,omitempty"`
+	To    interface{} `json:"to,omitempty" bson:"to,omitempty"`
+}
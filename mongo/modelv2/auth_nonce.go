@@ -0,0 +1,18 @@
+package modelv2
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// AuthNonce is a nonce issued for a web3token, a nonce is bound to the first
+// token using it before its expiry. Once used, the nonce expires with the
+// token.
+type AuthNonce struct {
+	ID       primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Nonce    string             `json:"nonce" bson:"nonce"`
+	ExpireAt time.Time          `json:"expire_at" bson:"expire_at"`
+	UsedAt   *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+	// Token is the signature of the token the nonce is bound to
+	Token string `json:"token,omitempty" bson:"token,omitempty"`
+}
@@ -0,0 +1,113 @@
+package modelv2
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CandleInterval string
+
+const (
+	CandleInterval1m CandleInterval = "1m"
+	CandleInterval5m CandleInterval = "5m"
+	CandleInterval1h CandleInterval = "1h"
+	CandleInterval1d CandleInterval = "1d"
+)
+
+// CandleIntervals are the intervals of the candles stored for every pool.
+var CandleIntervals = []CandleInterval{CandleInterval1m, CandleInterval5m, CandleInterval1h, CandleInterval1d}
+
+func ParseCandleInterval(interval string) (CandleInterval, error) {
+	for _, i := range CandleIntervals {
+		if string(i) == interval {
+			return i, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid candle interval %s", interval)
+}
+
+func (i CandleInterval) Duration() time.Duration {
+	switch i {
+	case CandleInterval1m:
+		return time.Minute
+	case CandleInterval5m:
+		return 5 * time.Minute
+	case CandleInterval1h:
+		return time.Hour
+	case CandleInterval1d:
+		return 24 * time.Hour
+	}
+
+	return 0
+}
+
+// Start returns the open time of the interval containing t.
+func (i CandleInterval) Start(t time.Time) time.Time {
+	return t.UTC().Truncate(i.Duration())
+}
+
+// CandleMeta identifies the pool and interval of a candle.
+type CandleMeta struct {
+	ChainID  string         `json:"chain_id" bson:"chain_id"`
+	PoolID   uint64         `json:"pool_id" bson:"pool_id"`
+	Interval CandleInterval `json:"interval" bson:"interval"`
+}
+
+// Candle is the OHLCV of a pool for an interval, the prices are the prices of
+// the base asset in quote asset and the volume is in USD.
+type Candle struct {
+	ID     primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	Meta   CandleMeta         `json:"meta" bson:"meta"`
+	Open   float64            `json:"open" bson:"open"`
+	High   float64            `json:"high" bson:"high"`
+	Low    float64            `json:"low" bson:"low"`
+	Close  float64            `json:"close" bson:"close"`
+	Volume float64            `json:"volume" bson:"volume"`
+	Trades int64              `json:"trades" bson:"trades"`
+
+	// Time is the open time of the interval
+	Time time.Time `json:"time" bson:"time"`
+	// Height is the swaps height the candle was built at
+	Height int64 `json:"height" bson:"height"`
+}
+
+// NewCandles builds the candles of the swaps of a pool, the swaps must be
+// sorted by time. Swaps without a price are not counted, no candle is built
+// for the intervals without swaps.
+func NewCandles(meta CandleMeta, swaps []*Swap) []*Candle {
+	candles := make([]*Candle, 0)
+
+	var candle *Candle
+	for _, swap := range swaps {
+		if swap.Price <= 0 {
+			continue
+		}
+
+		start := meta.Interval.Start(swap.Time)
+		if candle == nil || !candle.Time.Equal(start) {
+			candle = &Candle{
+				Meta: meta,
+				Open: swap.Price,
+				High: swap.Price,
+				Low:  swap.Price,
+				Time: start,
+			}
+			candles = append(candles, candle)
+		}
+
+		if swap.Price > candle.High {
+			candle.High = swap.Price
+		}
+		if swap.Price < candle.Low {
+			candle.Low = swap.Price
+		}
+		candle.Close = swap.Price
+		candle.Volume += swap.UsdValue
+		candle.Trades++
+	}
+
+	return candles
+}
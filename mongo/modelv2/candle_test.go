@@ -0,0 +1,48 @@
+package modelv2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCandles(t *testing.T) {
+	start := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	swap := func(offset time.Duration, price, usdValue float64) *Swap {
+		return &Swap{Time: start.Add(offset), Price: price, UsdValue: usdValue}
+	}
+
+	swaps := []*Swap{
+		swap(10*time.Second, 1.0, 10),
+		swap(20*time.Second, 1.4, 20),
+		swap(30*time.Second, 0, 50), // no price
+		swap(40*time.Second, 0.8, 30),
+		swap(50*time.Second, 1.2, 40),
+		swap(3*time.Minute+5*time.Second, 1.3, 5),
+	}
+
+	candles := NewCandles(CandleMeta{PoolID: 1, Interval: CandleInterval1m}, swaps)
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(candles))
+	}
+
+	first := candles[0]
+	if !first.Time.Equal(start) {
+		t.Fatalf("expected open time %s, got %s", start, first.Time)
+	}
+	if first.Open != 1.0 || first.High != 1.4 || first.Low != 0.8 || first.Close != 1.2 {
+		t.Fatalf("unexpected ohlc %v %v %v %v", first.Open, first.High, first.Low, first.Close)
+	}
+	if first.Volume != 100 || first.Trades != 4 {
+		t.Fatalf("unexpected volume %v, trades %d", first.Volume, first.Trades)
+	}
+
+	second := candles[1]
+	if !second.Time.Equal(start.Add(3*time.Minute)) || second.Open != 1.3 || second.Close != 1.3 || second.Trades != 1 {
+		t.Fatalf("unexpected candle %+v", second)
+	}
+
+	daily := NewCandles(CandleMeta{PoolID: 1, Interval: CandleInterval1d}, swaps)
+	if len(daily) != 1 || !daily[0].Time.Equal(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected daily candles %+v", daily)
+	}
+}
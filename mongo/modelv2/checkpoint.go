@@ -0,0 +1,24 @@
+package modelv2
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// Checkpoint is the last height processed by a sync module on a chain.
+type Checkpoint struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID   string             `json:"chain_id" bson:"chain_id" validate:"required"`
+	Module    string             `json:"module" bson:"module" validate:"required"`
+	Height    int64              `json:"height" bson:"height"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+type CheckpointFilter struct {
+	ChainID *string `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Module  *string `json:"module,omitempty" bson:"module,omitempty"`
+}
+
+func (cf *CheckpointFilter) Validate() error {
+	return nil
+}
@@ -2,13 +2,64 @@ package modelv2
 
 import (
 	"fmt"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Coin stores the exact amount as an integer string, AmountDec is a numeric
+// copy of the amount used by the aggregations ($sum, $gt, ...).
 type Coin struct {
-	Amount float64 `json:"amount" bson:"amount"`
-	Denom  string  `json:"denom" bson:"denom"`
+	Amount    string               `json:"amount" bson:"amount"`
+	AmountDec primitive.Decimal128 `json:"-" bson:"amount_dec"`
+	Denom     string               `json:"denom" bson:"denom"`
+}
+
+// NewCoin creates a coin from an integer amount, e.g. the string of an sdk.Int.
+func NewCoin(denom, amount string) (Coin, error) {
+	amountInt, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return Coin{}, fmt.Errorf("invalid coin amount %q", amount)
+	}
+
+	return Coin{
+		Amount:    amountInt.String(),
+		AmountDec: decimal128FromInt(amountInt),
+		Denom:     denom,
+	}, nil
+}
+
+// MustNewCoin is like NewCoin but panics if the amount is invalid.
+func MustNewCoin(denom, amount string) Coin {
+	coin, err := NewCoin(denom, amount)
+	if err != nil {
+		panic(err)
+	}
+
+	return coin
+}
+
+// Float64 returns the amount as float64, it must be used only to compute
+// approximated values, like USD values.
+func (c Coin) Float64() float64 {
+	amount, _ := strconv.ParseFloat(c.Amount, 64)
+	return amount
 }
 
 func (c Coin) String() string {
-	return fmt.Sprintf("%f%s", c.Amount, c.Denom)
+	return fmt.Sprintf("%s%s", c.Amount, c.Denom)
+}
+
+// decimal128FromInt converts amount to Decimal128, amounts with more than 34
+// digits are rounded.
+func decimal128FromInt(amount *big.Int) primitive.Decimal128 {
+	if dec, err := primitive.ParseDecimal128(amount.String()); err == nil {
+		return dec
+	}
+
+	rounded := new(big.Float).SetPrec(113).SetInt(amount).Text('e', 33)
+	dec, _ := primitive.ParseDecimal128(rounded)
+
+	return dec
 }
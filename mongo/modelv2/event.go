@@ -36,7 +36,7 @@ type StringEvent struct {
 }
 
 type EventFilter struct {
-	Id       *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
+	Id       *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
 	TxID     *primitive.ObjectID `json:"tx_id,omitempty" bson:"tx_id,omitempty"`
 	Key      *string             `json:"key,omitempty" bson:"key,omitempty"`
 	MsgIndex *int                `json:"msg_index,omitempty" bson:"msg_index,omitempty"`
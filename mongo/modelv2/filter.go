@@ -0,0 +1,10 @@
+package modelv2
+
+import "time"
+
+// TimeFilter matches the times from Gte (included) to Lt (excluded), a nil
+// bound is not checked.
+type TimeFilter struct {
+	Gte *time.Time `json:"gte,omitempty" bson:"$gte,omitempty"`
+	Lt  *time.Time `json:"lt,omitempty" bson:"$lt,omitempty"`
+}
@@ -1,20 +1,147 @@
 package modelv2
 
 import (
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"math/big"
+	"sort"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// HistoricalLiquidityInterval is the interval of the liquidity snapshots, a
+// pool has at most one snapshot per interval.
+const HistoricalLiquidityInterval = time.Hour
+
+// HistoricalLiquidity is the snapshot of the reserves of a pool after the last
+// event (join, exit or swap) of the interval opened at Time.
 type HistoricalLiquidity struct {
-	ID     primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	PoolID uint64             `json:"pool_id" bson:"pool_id"`
-	Assets []Coin             `json:"assets" bson:"assets" validate:"required"`
-	Time   time.Time          `json:"time" bson:"time" validate:"required"`
+	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID string             `json:"chain_id" bson:"chain_id"`
+	Height  int64              `json:"height" bson:"height"`
+	PoolID  uint64             `json:"pool_id" bson:"pool_id"`
+	Assets  []Coin             `json:"assets" bson:"assets" validate:"required"`
+	// TotalShares are the pool shares issued, empty when they are unknown
+	TotalShares string    `json:"total_shares,omitempty" bson:"total_shares,omitempty"`
+	UsdValue    float64   `json:"usd_value" bson:"usd_value"`
+	Time        time.Time `json:"time" bson:"time" validate:"required"`
 }
 
 type HistoricalLiquidityCreateReq struct {
-	ID     primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	PoolID uint64             `json:"pool_id" bson:"pool_id"`
-	Assets []Coin             `json:"assets" bson:"assets" validate:"required"`
-	Time   time.Time          `json:"time" bson:"time" validate:"required"`
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID     string             `json:"chain_id" bson:"chain_id"`
+	Height      int64              `json:"height" bson:"height"`
+	PoolID      uint64             `json:"pool_id" bson:"pool_id"`
+	Assets      []Coin             `json:"assets" bson:"assets" validate:"required"`
+	TotalShares string             `json:"total_shares,omitempty" bson:"total_shares,omitempty"`
+	UsdValue    float64            `json:"usd_value" bson:"usd_value"`
+	Time        time.Time          `json:"time" bson:"time" validate:"required"`
+}
+
+// Shares returns the total shares of the pool, nil when they are unknown.
+func (h *HistoricalLiquidity) Shares() *big.Int {
+	shares, ok := new(big.Int).SetString(h.TotalShares, 10)
+	if !ok {
+		return nil
+	}
+
+	return shares
+}
+
+// Reserves are the amounts of the assets of a pool by denom.
+type Reserves map[string]*big.Int
+
+func NewReserves(coins []Coin) Reserves {
+	r := make(Reserves)
+	r.Add(coins...)
+
+	return r
+}
+
+// Add adds coins to the reserves, e.g. the tokens of a join.
+func (r Reserves) Add(coins ...Coin) {
+	for _, coin := range coins {
+		amount := r.amount(coin.Denom)
+		amount.Add(amount, coinAmount(coin))
+	}
+}
+
+// Sub removes coins from the reserves, e.g. the tokens of an exit.
+func (r Reserves) Sub(coins ...Coin) {
+	for _, coin := range coins {
+		amount := r.amount(coin.Denom)
+		amount.Sub(amount, coinAmount(coin))
+	}
+}
+
+// Scale multiplies the reserves by f, the amounts are truncated.
+func (r Reserves) Scale(f float64) {
+	factor := new(big.Float).SetFloat64(f)
+	for denom, amount := range r {
+		scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(amount), factor).Int(nil)
+		r[denom] = scaled
+	}
+}
+
+// Float64 returns the amount of denom as float64.
+func (r Reserves) Float64(denom string) float64 {
+	amount, _ := new(big.Float).SetInt(r.get(denom)).Float64()
+	return amount
+}
+
+// Coins returns the reserves sorted by denom.
+func (r Reserves) Coins() []Coin {
+	denoms := make([]string, 0, len(r))
+	for denom := range r {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	coins := make([]Coin, len(denoms))
+	for i, denom := range denoms {
+		coins[i] = MustNewCoin(denom, r[denom].String())
+	}
+
+	return coins
+}
+
+// Equal reports whether r and o have the same amounts, missing denoms count
+// as zero.
+func (r Reserves) Equal(o Reserves) bool {
+	for denom, amount := range r {
+		if amount.Cmp(o.get(denom)) != 0 {
+			return false
+		}
+	}
+	for denom, amount := range o {
+		if amount.Cmp(r.get(denom)) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r Reserves) amount(denom string) *big.Int {
+	if _, ok := r[denom]; !ok {
+		r[denom] = new(big.Int)
+	}
+
+	return r[denom]
+}
+
+func (r Reserves) get(denom string) *big.Int {
+	if amount, ok := r[denom]; ok {
+		return amount
+	}
+
+	return new(big.Int)
+}
+
+func coinAmount(coin Coin) *big.Int {
+	amount, ok := new(big.Int).SetString(coin.Amount, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return amount
 }
@@ -0,0 +1,35 @@
+package modelv2
+
+import "testing"
+
+func TestReserves(t *testing.T) {
+	reserves := NewReserves([]Coin{MustNewCoin("uosmo", "1000"), MustNewCoin("ubtsg", "5000")})
+
+	// join, swap ubtsg for uosmo, exit
+	reserves.Add(MustNewCoin("uosmo", "100"), MustNewCoin("ubtsg", "500"))
+	reserves.Add(MustNewCoin("ubtsg", "250"))
+	reserves.Sub(MustNewCoin("uosmo", "40"))
+	reserves.Sub(MustNewCoin("uosmo", "60"), MustNewCoin("ubtsg", "750"))
+
+	coins := reserves.Coins()
+	if len(coins) != 2 {
+		t.Fatalf("expected 2 coins, got %d", len(coins))
+	}
+	if coins[0].String() != "5000ubtsg" || coins[1].String() != "1000uosmo" {
+		t.Fatalf("unexpected reserves %s", coins)
+	}
+
+	if !reserves.Equal(NewReserves(coins)) {
+		t.Fatal("expected equal reserves")
+	}
+
+	reserves.Add(MustNewCoin("uion", "0"))
+	if !reserves.Equal(NewReserves(coins)) {
+		t.Fatal("a zero amount must not change the reserves")
+	}
+
+	reserves.Sub(MustNewCoin("uosmo", "1"))
+	if reserves.Equal(NewReserves(coins)) {
+		t.Fatal("expected different reserves")
+	}
+}
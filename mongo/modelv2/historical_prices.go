@@ -19,7 +19,9 @@ type HistoricalPrice struct {
 	ID    primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	Asset string             `json:"asset" bson:"asset" validate:"required"`
 	Price float64            `json:"price" bson:"price" validate:"required"`
-	Time  time.Time          `json:"time" bson:"time" validate:"required"`
+	// Source is the price source the price comes from
+	Source string    `json:"source,omitempty" bson:"source,omitempty"`
+	Time   time.Time `json:"time" bson:"time" validate:"required"`
 }
 
 func (b *HistoricalPrice) Validate() error {
@@ -27,7 +29,7 @@ func (b *HistoricalPrice) Validate() error {
 }
 
 type HistoricalPriceFilter struct {
-	Id    *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
+	Id    *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
 	Asset *string             `json:"asset,omitempty" bson:"asset,omitempty"`
 	Time  *time.Time          `json:"time,omitempty" bson:"time,omitempty" validate:"required"`
 }
@@ -40,9 +42,89 @@ type HistoricalPriceCreateReq struct {
 	ID    primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	Asset string             `json:"asset" bson:"asset" validate:"required"`
 	Price float64            `json:"price" bson:"price" validate:"required"`
-	Time  time.Time          `json:"time" bson:"time" validate:"required"`
+	// Source is the price source the price comes from
+	Source string    `json:"source,omitempty" bson:"source,omitempty"`
+	Time   time.Time `json:"time" bson:"time" validate:"required"`
 }
 
 func (bc *HistoricalPriceCreateReq) Validate() error {
 	return utility.ValidateStruct(bc)
 }
+
+// PriceLookup tells how a price is looked up from the historical prices.
+// MaxAge is the max distance of a sample from the requested time, zero for
+// no limit. Interpolate interpolates the samples surrounding the requested
+// time, otherwise the nearest sample is used.
+type PriceLookup struct {
+	MaxAge      time.Duration
+	Interpolate bool
+}
+
+// PriceQuote is the price of an asset at a time. Age is the distance of the
+// nearest sample used from the requested time.
+type PriceQuote struct {
+	Asset        string
+	Price        float64
+	Time         time.Time
+	Age          time.Duration
+	Interpolated bool
+	Source       string
+}
+
+// Quote returns the price at time t from before, the last sample up to t, and
+// after, the first sample after t, either can be nil. The samples are
+// interpolated only when both are fresh, otherwise the nearest fresh sample
+// is used. false is returned when no sample is fresh.
+func (l PriceLookup) Quote(asset string, before, after *HistoricalPrice, t time.Time) (*PriceQuote, bool) {
+	var beforeAge, afterAge time.Duration
+	freshBefore := before != nil && before.Price > 0
+	if freshBefore {
+		beforeAge = t.Sub(before.Time)
+		freshBefore = l.fresh(beforeAge)
+	}
+	freshAfter := after != nil && after.Price > 0
+	if freshAfter {
+		afterAge = after.Time.Sub(t)
+		freshAfter = l.fresh(afterAge)
+	}
+
+	switch {
+	case freshBefore && beforeAge == 0:
+		return l.quote(asset, before, t, 0), true
+
+	case freshBefore && freshAfter && l.Interpolate:
+		f := float64(beforeAge) / float64(beforeAge+afterAge)
+		quote := l.quote(asset, before, t, beforeAge)
+		quote.Price = before.Price + f*(after.Price-before.Price)
+		quote.Interpolated = true
+		if afterAge < beforeAge {
+			quote.Age = afterAge
+		}
+		if before.Source != after.Source {
+			quote.Source = ""
+		}
+		return quote, true
+
+	case freshBefore && (!freshAfter || beforeAge <= afterAge):
+		return l.quote(asset, before, t, beforeAge), true
+
+	case freshAfter:
+		return l.quote(asset, after, t, afterAge), true
+	}
+
+	return nil, false
+}
+
+func (l PriceLookup) fresh(age time.Duration) bool {
+	return l.MaxAge == 0 || age <= l.MaxAge
+}
+
+func (l PriceLookup) quote(asset string, sample *HistoricalPrice, t time.Time, age time.Duration) *PriceQuote {
+	return &PriceQuote{
+		Asset:  asset,
+		Price:  sample.Price,
+		Time:   t,
+		Age:    age,
+		Source: sample.Source,
+	}
+}
@@ -0,0 +1,96 @@
+package modelv2
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceLookup_Quote(t *testing.T) {
+	now := time.Date(2022, 7, 11, 12, 0, 0, 0, time.UTC)
+	sample := func(offset time.Duration, price float64) *HistoricalPrice {
+		return &HistoricalPrice{Asset: "uosmo", Price: price, Time: now.Add(offset), Source: "coingecko"}
+	}
+
+	tests := []struct {
+		name         string
+		lookup       PriceLookup
+		before       *HistoricalPrice
+		after        *HistoricalPrice
+		ok           bool
+		price        float64
+		age          time.Duration
+		interpolated bool
+	}{
+		{
+			name:   "exact sample",
+			lookup: PriceLookup{MaxAge: time.Hour, Interpolate: true},
+			before: sample(0, 2),
+			after:  sample(time.Hour, 4),
+			ok:     true, price: 2, age: 0,
+		},
+		{
+			name:   "interpolated",
+			lookup: PriceLookup{MaxAge: time.Hour, Interpolate: true},
+			before: sample(-45*time.Minute, 2),
+			after:  sample(15*time.Minute, 4),
+			ok:     true, price: 3.5, age: 15 * time.Minute, interpolated: true,
+		},
+		{
+			name:   "nearest sample without interpolation",
+			lookup: PriceLookup{MaxAge: time.Hour},
+			before: sample(-45*time.Minute, 2),
+			after:  sample(15*time.Minute, 4),
+			ok:     true, price: 4, age: 15 * time.Minute,
+		},
+		{
+			name:   "stale sample after is not interpolated",
+			lookup: PriceLookup{MaxAge: time.Hour, Interpolate: true},
+			before: sample(-30*time.Minute, 2),
+			after:  sample(48*time.Hour, 4),
+			ok:     true, price: 2, age: 30 * time.Minute,
+		},
+		{
+			name:   "before the first sample",
+			lookup: PriceLookup{MaxAge: time.Hour, Interpolate: true},
+			after:  sample(10*time.Minute, 4),
+			ok:     true, price: 4, age: 10 * time.Minute,
+		},
+		{
+			name:   "stale",
+			lookup: PriceLookup{MaxAge: time.Hour, Interpolate: true},
+			before: sample(-365*24*time.Hour, 2),
+			ok:     false,
+		},
+		{
+			name:   "no limit",
+			lookup: PriceLookup{},
+			before: sample(-365*24*time.Hour, 2),
+			ok:     true, price: 2, age: 365 * 24 * time.Hour,
+		},
+		{
+			name:   "no sample",
+			lookup: PriceLookup{},
+			ok:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, ok := tt.lookup.Quote("uosmo", tt.before, tt.after, now)
+			if ok != tt.ok {
+				t.Fatalf("Quote() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if math.Abs(quote.Price-tt.price) > 1e-9 || quote.Age != tt.age || quote.Interpolated != tt.interpolated {
+				t.Errorf("Quote() = %+v, want price %v, age %s, interpolated %v", quote, tt.price, tt.age, tt.interpolated)
+			}
+			if quote.Source != "coingecko" || !quote.Time.Equal(now) {
+				t.Errorf("Quote() = %+v, want the source and the requested time", quote)
+			}
+		})
+	}
+}
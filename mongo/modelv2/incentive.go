@@ -11,8 +11,9 @@ type Incentive struct {
 	ChainID string             `json:"chain_id" bson:"chain_id" validate:"required"`
 	Height  int64              `json:"height" bson:"height" validate:"required"`
 
-	Receiver string `json:"receiver" bson:"receiver" validate:"required"`
-	Assets   []Coin `json:"assets" bson:"assets"`
+	Receiver string  `json:"receiver" bson:"receiver" validate:"required"`
+	Assets   []Coin  `json:"assets" bson:"assets"`
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
@@ -22,7 +23,8 @@ func (e *Incentive) Validate() error {
 }
 
 type IncentiveFilter struct {
-	Id       *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
+	Id       *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID  *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
 	Height   *int64              `json:"height,omitempty" bson:"height,omitempty"`
 	Receiver *string             `json:"receiver,omitempty" bson:"receiver,omitempty"`
 }
@@ -36,8 +38,9 @@ type IncentiveCreateReq struct {
 	ChainID string             `json:"chain_id" bson:"chain_id" validate:"required"`
 	Height  int64              `json:"height" bson:"height" validate:"required"`
 
-	Receiver string `json:"receiver" bson:"receiver" validate:"required"`
-	Assets   []Coin `json:"assets" bson:"assets"`
+	Receiver string  `json:"receiver" bson:"receiver" validate:"required"`
+	Assets   []Coin  `json:"assets" bson:"assets"`
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
@@ -0,0 +1,164 @@
+package modelv2
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type LeaderboardMetric string
+
+const (
+	// LeaderboardMetricVolume ranks the traders by USD volume swapped
+	LeaderboardMetricVolume LeaderboardMetric = "volume"
+	// LeaderboardMetricLiquidity ranks the LPs by USD value joined
+	LeaderboardMetricLiquidity LeaderboardMetric = "liquidity"
+	// LeaderboardMetricClaims ranks the accounts by merkledrop claims
+	LeaderboardMetricClaims LeaderboardMetric = "claims"
+)
+
+var LeaderboardMetrics = []LeaderboardMetric{LeaderboardMetricVolume, LeaderboardMetricLiquidity, LeaderboardMetricClaims}
+
+func ParseLeaderboardMetric(metric string) (LeaderboardMetric, error) {
+	for _, m := range LeaderboardMetrics {
+		if string(m) == metric {
+			return m, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid leaderboard metric %s", metric)
+}
+
+// LeaderboardWindow is a number of UTC days ending with the current day.
+type LeaderboardWindow string
+
+const (
+	LeaderboardWindowDay   LeaderboardWindow = "day"
+	LeaderboardWindowWeek  LeaderboardWindow = "week"
+	LeaderboardWindowMonth LeaderboardWindow = "month"
+	LeaderboardWindowAll   LeaderboardWindow = "all"
+)
+
+var LeaderboardWindows = []LeaderboardWindow{LeaderboardWindowDay, LeaderboardWindowWeek, LeaderboardWindowMonth, LeaderboardWindowAll}
+
+func ParseLeaderboardWindow(window string) (LeaderboardWindow, error) {
+	for _, w := range LeaderboardWindows {
+		if string(w) == window {
+			return w, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid leaderboard window %s", window)
+}
+
+// Since returns the first day of the window ending on the day of now, nil is
+// returned for the window of all the days.
+func (w LeaderboardWindow) Since(now time.Time) *time.Time {
+	days := 0
+	switch w {
+	case LeaderboardWindowDay:
+		days = 1
+	case LeaderboardWindowWeek:
+		days = 7
+	case LeaderboardWindowMonth:
+		days = 30
+	default:
+		return nil
+	}
+
+	since := LeaderboardDay(now).AddDate(0, 0, 1-days)
+	return &since
+}
+
+// LeaderboardDay returns the day of the bucket containing t.
+func LeaderboardDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+const (
+	LeaderboardDefaultLimit = 100
+	LeaderboardMaxLimit     = 1000
+)
+
+// LeaderboardLimit returns the number of entries to rank, the default one when
+// limit is nil.
+func LeaderboardLimit(limit *int) (int64, error) {
+	if limit == nil {
+		return LeaderboardDefaultLimit, nil
+	}
+	if *limit <= 0 || *limit > LeaderboardMaxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", LeaderboardMaxLimit)
+	}
+
+	return int64(*limit), nil
+}
+
+// LeaderboardBucket is the value of a metric for an account in a pool during
+// a day, the buckets of a day are rebuilt every time the day is synced. The
+// buckets of the metrics not related to a pool have pool 0.
+type LeaderboardBucket struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID    string             `json:"chain_id" bson:"chain_id"`
+	Metric     LeaderboardMetric  `json:"metric" bson:"metric"`
+	PoolID     uint64             `json:"pool_id" bson:"pool_id"`
+	Account    string             `json:"account" bson:"account"`
+	AccountKey string             `json:"account_key" bson:"account_key"`
+	Day        time.Time          `json:"day" bson:"day"`
+	Value      float64            `json:"value" bson:"value"`
+	Count      int64              `json:"count" bson:"count"`
+}
+
+type LeaderboardFilter struct {
+	ChainID string
+	Metric  LeaderboardMetric
+	PoolID  *uint64
+	Since   *time.Time
+	Limit   int64
+}
+
+// NewLeaderboardFilter parses the leaderboard arguments, the window ends on
+// the day of now.
+func NewLeaderboardFilter(metric, window string, poolID *int, limit *int, now time.Time) (*LeaderboardFilter, error) {
+	leaderboardMetric, err := ParseLeaderboardMetric(metric)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboardWindow, err := ParseLeaderboardWindow(window)
+	if err != nil {
+		return nil, err
+	}
+
+	leaderboardLimit, err := LeaderboardLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &LeaderboardFilter{
+		Metric: leaderboardMetric,
+		Since:  leaderboardWindow.Since(now),
+		Limit:  leaderboardLimit,
+	}
+
+	if poolID != nil {
+		if *poolID < 0 {
+			return nil, fmt.Errorf("invalid pool id %d", *poolID)
+		}
+
+		id := uint64(*poolID)
+		filter.PoolID = &id
+	}
+
+	return filter, nil
+}
+
+// LeaderboardEntry is the rank of an account, Count is the number of swaps,
+// joins or claims summed in Value.
+type LeaderboardEntry struct {
+	Rank       int     `json:"rank" bson:"-"`
+	Account    string  `json:"account" bson:"account"`
+	AccountKey string  `json:"account_key" bson:"account_key"`
+	Value      float64 `json:"value" bson:"value"`
+	Count      int64   `json:"count" bson:"count"`
+}
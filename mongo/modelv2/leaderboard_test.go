@@ -0,0 +1,58 @@
+package modelv2
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaderboardWindowSince(t *testing.T) {
+	now := time.Date(2022, 7, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		window LeaderboardWindow
+		since  time.Time
+	}{
+		{LeaderboardWindowDay, time.Date(2022, 7, 15, 0, 0, 0, 0, time.UTC)},
+		{LeaderboardWindowWeek, time.Date(2022, 7, 9, 0, 0, 0, 0, time.UTC)},
+		{LeaderboardWindowMonth, time.Date(2022, 6, 16, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if since := tt.window.Since(now); since == nil || !since.Equal(tt.since) {
+			t.Errorf("%s: expected %s, got %v", tt.window, tt.since, since)
+		}
+	}
+
+	if since := LeaderboardWindowAll.Since(now); since != nil {
+		t.Errorf("all: expected no start, got %s", since)
+	}
+}
+
+func TestNewLeaderboardFilter(t *testing.T) {
+	now := time.Date(2022, 7, 15, 18, 30, 0, 0, time.UTC)
+	poolID := 1
+
+	filter, err := NewLeaderboardFilter("volume", "week", &poolID, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Metric != LeaderboardMetricVolume || *filter.PoolID != 1 || filter.Limit != LeaderboardDefaultLimit {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+
+	limit := LeaderboardMaxLimit + 1
+	invalid := []struct {
+		metric, window string
+		limit          *int
+	}{
+		{"fees", "week", nil},
+		{"volume", "year", nil},
+		{"claims", "all", &limit},
+	}
+
+	for _, tt := range invalid {
+		if _, err := NewLeaderboardFilter(tt.metric, tt.window, nil, tt.limit, now); err == nil {
+			t.Errorf("%s %s: expected an error", tt.metric, tt.window)
+		}
+	}
+}
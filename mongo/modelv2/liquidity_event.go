@@ -6,6 +6,11 @@ import (
 	"time"
 )
 
+const (
+	LiquidityEventTypeJoin = "join"
+	LiquidityEventTypeExit = "exit"
+)
+
 type LiquidityEvent struct {
 	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
 	ChainID string             `json:"chain_id" bson:"chain_id" validate:"required"`
@@ -14,9 +19,15 @@ type LiquidityEvent struct {
 
 	Type      string `json:"type" bson:"type"`
 	Sender    string `json:"sender" bson:"sender" validate:"required"`
+	SenderKey string `json:"sender_key" bson:"sender_key"`
 	PoolID    uint64 `json:"pool_id" bson:"pool_id" validate:"required"`
 	TokensIn  []Coin `json:"tokens_in" bson:"tokens_in"`
 	TokensOut []Coin `json:"tokens_out" bson:"tokens_out"`
+	// Shares are the pool shares minted by a join or burned by an exit, nil
+	// for the events synced before the shares were indexed
+	Shares *Coin `json:"shares,omitempty" bson:"shares,omitempty"`
+	// UsdValue is the value of the tokens joined or exited
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
@@ -26,9 +37,15 @@ func (e *LiquidityEvent) Validate() error {
 }
 
 type LiquidityEventFilter struct {
-	Id     *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	Height *int64              `json:"height,omitempty" bson:"height,omitempty"`
-	Sender *string             `json:"sender,omitempty" bson:"sender,omitempty"`
+	Id        *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID   *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height    *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	TxHash    *string             `json:"tx_hash,omitempty" bson:"tx_hash,omitempty"`
+	Type      *string             `json:"type,omitempty" bson:"type,omitempty"`
+	Sender    *string             `json:"sender,omitempty" bson:"sender,omitempty"`
+	SenderKey *string             `json:"sender_key,omitempty" bson:"sender_key,omitempty"`
+	PoolID    *uint64             `json:"pool_id,omitempty" bson:"pool_id,omitempty"`
+	Time      *TimeFilter         `json:"time,omitempty" bson:"time,omitempty"`
 }
 
 func (ef *LiquidityEventFilter) Validate() error {
@@ -41,10 +58,14 @@ type LiquidityEventCreateReq struct {
 	Height  int64              `json:"height" bson:"height" validate:"required"`
 	TxHash  string             `json:"tx_hash" bson:"tx_hash"`
 
-	Sender    string `json:"sender" bson:"sender" validate:"required"`
-	PoolID    uint64 `json:"pool_id" bson:"pool_id" validate:"required"`
-	TokensIn  []Coin `json:"tokens_in" bson:"tokens_in"`
-	TokensOut []Coin `json:"tokens_out" bson:"tokens_out"`
+	Type      string  `json:"type" bson:"type"`
+	Sender    string  `json:"sender" bson:"sender" validate:"required"`
+	SenderKey string  `json:"sender_key" bson:"sender_key"`
+	PoolID    uint64  `json:"pool_id" bson:"pool_id" validate:"required"`
+	TokensIn  []Coin  `json:"tokens_in" bson:"tokens_in"`
+	TokensOut []Coin  `json:"tokens_out" bson:"tokens_out"`
+	Shares    *Coin   `json:"shares,omitempty" bson:"shares,omitempty"`
+	UsdValue  float64 `json:"usd_value" bson:"usd_value"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
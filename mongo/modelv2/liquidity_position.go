@@ -0,0 +1,155 @@
+package modelv2
+
+import (
+	"math/big"
+	"time"
+)
+
+// LiquidityPosition is the position of an account in a pool derived from its
+// joins and exits. The position holds the pool shares minted by the joins and
+// not burned by the exits, its share of the pool is the held shares over the
+// total shares. The values are in USD, valued at the time of every join and
+// exit.
+type LiquidityPosition struct {
+	Sender string `json:"sender"`
+	PoolID uint64 `json:"pool_id"`
+	Joins  int64  `json:"joins"`
+	Exits  int64  `json:"exits"`
+
+	Deposited    float64 `json:"deposited"`
+	Withdrawn    float64 `json:"withdrawn"`
+	NetDeposited float64 `json:"net_deposited"`
+	// CostBasis is the value deposited not yet withdrawn
+	CostBasis   float64 `json:"cost_basis"`
+	RealizedPnl float64 `json:"realized_pnl"`
+
+	Share         float64 `json:"share"`
+	Value         float64 `json:"value"`
+	UnrealizedPnl float64 `json:"unrealized_pnl"`
+
+	FirstTime time.Time `json:"first_time"`
+	LastTime  time.Time `json:"last_time"`
+
+	held Reserves
+	// shares held, nil when an event without shares was applied
+	shares *big.Int
+}
+
+func NewLiquidityPosition(sender string, poolID uint64) *LiquidityPosition {
+	return &LiquidityPosition{Sender: sender, PoolID: poolID, held: make(Reserves), shares: new(big.Int)}
+}
+
+// Tokens returns the tokens of the position, the tokens joined reduced by the
+// exits.
+func (p *LiquidityPosition) Tokens() []Coin {
+	return p.held.Coins()
+}
+
+// Apply adds a join or an exit to the position, the events must be applied in
+// order. weights are the normalized weights of the pool assets.
+func (p *LiquidityPosition) Apply(evt *LiquidityEvent, weights map[string]float64) {
+	if p.FirstTime.IsZero() {
+		p.FirstTime = evt.Time
+	}
+	p.LastTime = evt.Time
+
+	switch {
+	case evt.Type == LiquidityEventTypeJoin || (evt.Type == "" && len(evt.TokensIn) > 0):
+		p.Joins++
+		p.Deposited += evt.UsdValue
+		p.CostBasis += evt.UsdValue
+		p.held.Add(evt.TokensIn...)
+		p.addShares(evt.Shares, 1)
+
+	case evt.Type == LiquidityEventTypeExit || (evt.Type == "" && len(evt.TokensOut) > 0):
+		f := p.fraction(evt, weights)
+
+		p.Exits++
+		p.Withdrawn += evt.UsdValue
+		p.RealizedPnl += evt.UsdValue - f*p.CostBasis
+		p.CostBasis -= f * p.CostBasis
+		p.held.Scale(1 - f)
+		p.addShares(evt.Shares, -1)
+	}
+
+	p.NetDeposited = p.Deposited - p.Withdrawn
+}
+
+// Estimate sets the share and the value of the position in a pool with the
+// given total shares and TVL, they are left to zero when the shares of the
+// position or of the pool are unknown.
+func (p *LiquidityPosition) Estimate(totalShares *big.Int, tvl float64) {
+	if p.shares == nil || totalShares == nil || totalShares.Sign() <= 0 {
+		return
+	}
+
+	share, _ := new(big.Rat).SetFrac(p.shares, totalShares).Float64()
+
+	p.Share = clamp(share)
+	p.Value = p.Share * tvl
+	p.UnrealizedPnl = p.Value - p.CostBasis
+}
+
+// fraction returns the fraction of the position exited by evt, the shares
+// burned over the shares held or, when the shares are unknown, the fraction
+// of the tokens held.
+func (p *LiquidityPosition) fraction(evt *LiquidityEvent, weights map[string]float64) float64 {
+	if evt.Shares != nil && p.shares != nil && p.shares.Sign() > 0 {
+		f, _ := new(big.Rat).SetFrac(coinAmount(*evt.Shares), p.shares).Float64()
+		return clamp(f)
+	}
+
+	// every asset of the position is worth held/weight, so exiting a fraction
+	// f of the position gives f*held/weight of any single asset
+	w := p.weights(weights)
+
+	f := float64(0)
+	for _, coin := range evt.TokensOut {
+		if held := p.held.Float64(coin.Denom); held > 0 {
+			f += w[coin.Denom] * coin.Float64() / held
+		}
+	}
+
+	return clamp(f)
+}
+
+// addShares adds the shares minted (sign 1) or burned (sign -1) to the held
+// shares, they become unknown when shares is nil.
+func (p *LiquidityPosition) addShares(shares *Coin, sign int64) {
+	if shares == nil || p.shares == nil {
+		p.shares = nil
+		return
+	}
+
+	amount := coinAmount(*shares)
+	p.shares.Add(p.shares, amount.Mul(amount, big.NewInt(sign)))
+	if p.shares.Sign() < 0 {
+		p.shares.SetInt64(0)
+	}
+}
+
+// weights returns the pool weights, or equal weights for the held assets when
+// the weights are unknown.
+func (p *LiquidityPosition) weights(weights map[string]float64) map[string]float64 {
+	if len(weights) > 0 {
+		return weights
+	}
+
+	equal := make(map[string]float64)
+	for denom := range p.held {
+		equal[denom] = 1 / float64(len(p.held))
+	}
+
+	return equal
+}
+
+func clamp(f float64) float64 {
+	if f < 0 {
+		return 0
+	}
+	if f > 1 {
+		return 1
+	}
+
+	return f
+}
@@ -0,0 +1,96 @@
+package modelv2
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestLiquidityPosition(t *testing.T) {
+	weights := map[string]float64{"uatom": 0.5, "uosmo": 0.5}
+	position := NewLiquidityPosition("osmo1", 1)
+
+	position.Apply(&LiquidityEvent{
+		Type:     LiquidityEventTypeJoin,
+		TokensIn: []Coin{MustNewCoin("uatom", "100"), MustNewCoin("uosmo", "100")},
+		Shares:   shares(10),
+		UsdValue: 200,
+	}, weights)
+
+	// the share does not depend on the reserves, which change with the price
+	position.Estimate(big.NewInt(100), 3000)
+	assertFloat(t, "share", position.Share, 0.1)
+	assertFloat(t, "value", position.Value, 300)
+	assertFloat(t, "unrealized pnl", position.UnrealizedPnl, 100)
+
+	// half of the position exited in both assets
+	position.Apply(&LiquidityEvent{
+		Type:      LiquidityEventTypeExit,
+		TokensOut: []Coin{MustNewCoin("uatom", "50"), MustNewCoin("uosmo", "50")},
+		Shares:    shares(5),
+		UsdValue:  300,
+	}, weights)
+
+	assertFloat(t, "realized pnl", position.RealizedPnl, 200)
+	assertFloat(t, "cost basis", position.CostBasis, 100)
+	assertFloat(t, "net deposited", position.NetDeposited, -100)
+
+	// the rest of the position exited in a single asset
+	position.Apply(&LiquidityEvent{
+		Type:      LiquidityEventTypeExit,
+		TokensOut: []Coin{MustNewCoin("uatom", "100")},
+		Shares:    shares(5),
+		UsdValue:  250,
+	}, weights)
+
+	assertFloat(t, "realized pnl", position.RealizedPnl, 350)
+	assertFloat(t, "cost basis", position.CostBasis, 0)
+
+	for _, coin := range position.Tokens() {
+		if coin.Amount != "0" {
+			t.Fatalf("expected an empty position, got %s", coin)
+		}
+	}
+
+	if position.Joins != 1 || position.Exits != 2 {
+		t.Fatalf("expected 1 join and 2 exits, got %d and %d", position.Joins, position.Exits)
+	}
+}
+
+func TestLiquidityPositionUnknownShares(t *testing.T) {
+	weights := map[string]float64{"uatom": 0.5, "uosmo": 0.5}
+	position := NewLiquidityPosition("osmo1", 1)
+
+	// a join synced before the shares were indexed
+	position.Apply(&LiquidityEvent{
+		Type:     LiquidityEventTypeJoin,
+		TokensIn: []Coin{MustNewCoin("uatom", "100"), MustNewCoin("uosmo", "100")},
+		UsdValue: 200,
+	}, weights)
+
+	position.Apply(&LiquidityEvent{
+		Type:      LiquidityEventTypeExit,
+		TokensOut: []Coin{MustNewCoin("uatom", "50"), MustNewCoin("uosmo", "50")},
+		Shares:    shares(5),
+		UsdValue:  100,
+	}, weights)
+
+	assertFloat(t, "cost basis", position.CostBasis, 100)
+
+	position.Estimate(big.NewInt(100), 3000)
+	assertFloat(t, "share", position.Share, 0)
+	assertFloat(t, "value", position.Value, 0)
+}
+
+func shares(amount int64) *Coin {
+	coin := MustNewCoin("gamm/pool/1", big.NewInt(amount).String())
+	return &coin
+}
+
+func assertFloat(t *testing.T, name string, got, expected float64) {
+	t.Helper()
+
+	if math.Abs(got-expected) > 1e-9 {
+		t.Fatalf("expected %s %f, got %f", name, expected, got)
+	}
+}
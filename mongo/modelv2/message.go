@@ -0,0 +1,37 @@
+package modelv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Message is a message of a transaction, it is built from the "message"
+// events stored with the transaction.
+type Message struct {
+	ChainID  string    `json:"chain_id" bson:"chain_id"`
+	Height   int64     `json:"height" bson:"height"`
+	TxHash   string    `json:"tx_hash" bson:"tx_hash"`
+	MsgIndex int       `json:"msg_index" bson:"msg_index"`
+	MsgType  string    `json:"msg_type" bson:"msg_type"`
+	Module   string    `json:"module" bson:"module"`
+	Signer   string    `json:"signer" bson:"signer"`
+	Time     time.Time `json:"time" bson:"time"`
+
+	// TxID is the _id of the transaction, it orders the messages of the
+	// transactions stored at the same height.
+	TxID primitive.ObjectID `json:"-" bson:"tx_id"`
+}
+
+type MessageFilter struct {
+	ChainID  *string `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height   *int64  `json:"height,omitempty" bson:"height,omitempty"`
+	TxHash   *string `json:"tx_hash,omitempty" bson:"tx_hash,omitempty"`
+	MsgIndex *int    `json:"msg_index,omitempty" bson:"msg_index,omitempty"`
+	MsgType  *string `json:"msg_type,omitempty" bson:"msg_type,omitempty"`
+	Signer   *string `json:"signer,omitempty" bson:"signer,omitempty"`
+}
+
+func (mf *MessageFilter) Validate() error {
+	return nil
+}
@@ -3,6 +3,7 @@ package modelv2
 import (
 	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"strconv"
 	"time"
 )
 
@@ -19,6 +20,9 @@ type Pool struct {
 	PoolAssets []PoolAsset `json:"pool_assets" bson:"pool_assets" validate:"required"`
 	SwapFee    float64     `json:"swap_fee" bson:"swap_fee" validate:"required"`
 	ExitFee    float64     `json:"exit_fee" bson:"exit_fee"`
+	// AssetsHeight is the height the assets, the weights and the fees were
+	// queried at, 0 for the pools synced before it was stored
+	AssetsHeight int64 `json:"assets_height" bson:"assets_height"`
 
 	Time     time.Time `json:"time,omitempty" bson:"time,omitempty"`
 	Tracked  bool      `json:"tracked" bson:"tracked"`
@@ -55,14 +59,36 @@ func (e *Pool) GetQuoteAsset() *Coin {
 	return nil
 }
 
+// Weights returns the normalized weights of the pool assets by denom.
+func (e *Pool) Weights() map[string]float64 {
+	weights := make(map[string]float64)
+
+	total := float64(0)
+	for _, asset := range e.PoolAssets {
+		weight, _ := strconv.ParseFloat(asset.Weight, 64)
+		weights[asset.Token.Denom] = weight
+		total += weight
+	}
+
+	for denom := range weights {
+		if total > 0 {
+			weights[denom] /= total
+		}
+	}
+
+	return weights
+}
+
 type PoolAsset struct {
 	Token  Coin   `json:"token" bson:"token" validate:"required"`
 	Weight string `json:"weight" bson:"weight" validate:"required"`
 }
 
 type PoolFilter struct {
-	Id     *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	PoolID *uint64             `json:"pool_id,omitempty" bson:"pool_id,omitempty"`
+	Id      *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	PoolID  *uint64             `json:"pool_id,omitempty" bson:"pool_id,omitempty"`
+	Tracked *bool               `json:"tracked,omitempty" bson:"tracked,omitempty"`
 }
 
 func (ef *PoolFilter) Validate() error {
@@ -80,6 +106,8 @@ type PoolCreateReq struct {
 	SwapFee    float64     `json:"swap_fee" bson:"swap_fee"`
 	ExitFee    float64     `json:"exit_fee" bson:"exit_fee"`
 
+	AssetsHeight int64 `json:"assets_height" bson:"assets_height"`
+
 	Time     time.Time `json:"time" bson:"time"`
 	Tracked  bool      `json:"tracked" bson:"tracked"`
 	Inverted bool      `json:"inverted" bson:"inverted"`
@@ -0,0 +1,60 @@
+package modelv2
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PoolStats are the stats of a pool computed at the block Height of Time. The
+// values are in USD and the APRs in percent, the APRs are annualized from the
+// last 7 days.
+type PoolStats struct {
+	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID string             `json:"chain_id" bson:"chain_id"`
+	PoolID  uint64             `json:"pool_id" bson:"pool_id"`
+
+	Tvl            float64 `json:"tvl" bson:"tvl"`
+	Volume24h      float64 `json:"volume_24h" bson:"volume_24h"`
+	Volume7d       float64 `json:"volume_7d" bson:"volume_7d"`
+	Fees24h        float64 `json:"fees_24h" bson:"fees_24h"`
+	Fees7d         float64 `json:"fees_7d" bson:"fees_7d"`
+	Incentives7d   float64 `json:"incentives_7d" bson:"incentives_7d"`
+	FeeApr         float64 `json:"fee_apr" bson:"fee_apr"`
+	IncentiveApr   float64 `json:"incentive_apr" bson:"incentive_apr"`
+	Price          float64 `json:"price" bson:"price"`
+	PriceChange24h float64 `json:"price_change_24h" bson:"price_change_24h"` // percent
+
+	Height int64     `json:"height" bson:"height"`
+	Time   time.Time `json:"time" bson:"time"`
+}
+
+type PoolStatsFilter struct {
+	ChainID *string `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	PoolID  *uint64 `json:"pool_id,omitempty" bson:"pool_id,omitempty"`
+}
+
+// SwapVolume is the volume of the swaps of a pool paying the fee in Denom,
+// Fee is the sum of the fees in Denom raw units.
+type SwapVolume struct {
+	PoolID   int64   `json:"pool_id" bson:"pool_id"`
+	Denom    string  `json:"denom" bson:"denom"`
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
+	Fee      float64 `json:"fee" bson:"fee"`
+	Trades   int64   `json:"trades" bson:"trades"`
+}
+
+// ReceiverIncentives is the USD value of the incentives received by Receiver.
+type ReceiverIncentives struct {
+	Receiver string  `json:"receiver" bson:"_id"`
+	UsdValue float64 `json:"usd_value" bson:"usd_value"`
+}
+
+// LiquidityBalance is the amount of Denom joined by Sender in a pool minus
+// the amount exited.
+type LiquidityBalance struct {
+	Sender string               `json:"sender" bson:"sender"`
+	PoolID uint64               `json:"pool_id" bson:"pool_id"`
+	Denom  string               `json:"denom" bson:"denom"`
+	Amount primitive.Decimal128 `json:"amount" bson:"amount"`
+}
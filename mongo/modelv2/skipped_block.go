@@ -0,0 +1,17 @@
+package modelv2
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
+
+// SkippedBlock is a block the indexer could not decode, it is retried by the
+// next runs until it is indexed.
+type SkippedBlock struct {
+	ID      primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ChainID string             `json:"chain_id" bson:"chain_id"`
+	Height  int64              `json:"height" bson:"height"`
+	// Error is the decode error of the last attempt
+	Error     string    `json:"error" bson:"error"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
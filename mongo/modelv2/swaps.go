@@ -12,13 +12,19 @@ type Swap struct {
 	Height  int64              `json:"height" bson:"height" validate:"required"`
 	TxHash  string             `json:"tx_hash" bson:"tx_hash" validate:"required"`
 
-	Account  string  `json:"account" bson:"account" validate:"required"`
-	PoolId   int64   `json:"pool_id" bson:"pool_id" validate:"required"`
-	Type     int     `json:"type" bson:"type"` // 0 - buy, 1 - sell
-	TokenIn  Coin    `json:"token_in" bson:"token_in" validate:"required"`
-	TokenOut Coin    `json:"token_out" bson:"token_out"`
-	Fee      float64 `json:"fee" bson:"fee"`
-	UsdValue float64 `json:"usd_value" bson:"usd_value"`
+	Account    string  `json:"account" bson:"account" validate:"required"`
+	AccountKey string  `json:"account_key" bson:"account_key"`
+	PoolId     int64   `json:"pool_id" bson:"pool_id" validate:"required"`
+	Type       int     `json:"type" bson:"type"` // 0 - buy, 1 - sell
+	TokenIn    Coin    `json:"token_in" bson:"token_in" validate:"required"`
+	TokenOut   Coin    `json:"token_out" bson:"token_out"`
+	Fee        float64 `json:"fee" bson:"fee"`
+	UsdValue   float64 `json:"usd_value" bson:"usd_value"`
+	// UsdPriceAge is the age in seconds of the price used for UsdValue, it is
+	// not set when the swap has no price
+	UsdPriceAge *int64 `json:"usd_price_age,omitempty" bson:"usd_price_age,omitempty"`
+	// Price is the price of the base asset in quote asset, in display units
+	Price float64 `json:"price" bson:"price"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
@@ -28,8 +34,14 @@ func (e *Swap) Validate() error {
 }
 
 type SwapFilter struct {
-	Id     *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	Height *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	Id         *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID    *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height     *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	TxHash     *string             `json:"tx_hash,omitempty" bson:"tx_hash,omitempty"`
+	Account    *string             `json:"account,omitempty" bson:"account,omitempty"`
+	AccountKey *string             `json:"account_key,omitempty" bson:"account_key,omitempty"`
+	PoolId     *int64              `json:"pool_id,omitempty" bson:"pool_id,omitempty"`
+	Type       *int                `json:"type,omitempty" bson:"type,omitempty"`
 }
 
 func (ef *SwapFilter) Validate() error {
@@ -42,13 +54,19 @@ type SwapCreateReq struct {
 	Height  int64              `json:"height" bson:"height" validate:"required"`
 	TxHash  string             `json:"tx_hash" bson:"tx_hash" validate:"required"`
 
-	Account  string  `json:"account" bson:"account" validate:"required"`
-	PoolId   int64   `json:"pool_id" bson:"pool_id" validate:"required"`
-	Type     int     `json:"type" bson:"type"` // 0 - buy, 1 - sell
-	TokenIn  Coin    `json:"token_in" bson:"token_in" validate:"required"`
-	TokenOut Coin    `json:"token_out" bson:"token_out" validate:"required"`
-	Fee      float64 `json:"fee" bson:"fee"`
-	UsdValue float64 `json:"usd_value" bson:"usd_value"`
+	Account    string  `json:"account" bson:"account" validate:"required"`
+	AccountKey string  `json:"account_key" bson:"account_key"`
+	PoolId     int64   `json:"pool_id" bson:"pool_id" validate:"required"`
+	Type       int     `json:"type" bson:"type"` // 0 - buy, 1 - sell
+	TokenIn    Coin    `json:"token_in" bson:"token_in" validate:"required"`
+	TokenOut   Coin    `json:"token_out" bson:"token_out" validate:"required"`
+	Fee        float64 `json:"fee" bson:"fee"`
+	UsdValue   float64 `json:"usd_value" bson:"usd_value"`
+	// UsdPriceAge is the age in seconds of the price used for UsdValue, it is
+	// not set when the swap has no price
+	UsdPriceAge *int64 `json:"usd_price_age,omitempty" bson:"usd_price_age,omitempty"`
+	// Price is the price of the base asset in quote asset, in display units
+	Price float64 `json:"price" bson:"price"`
 
 	Time time.Time `json:"time" bson:"time" validate:"required"`
 }
@@ -1,7 +1,6 @@
 package modelv2
 
 import (
-	"github.com/angelorc/sinfonia-go/mongo/types"
 	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"time"
@@ -14,11 +13,11 @@ type Transaction struct {
 	Hash    string             `json:"hash" bson:"hash" validate:"required"`
 	Code    int                `json:"code" bson:"code"`
 	//Logs      []ABCIMessageLog   `json:"logs" bson:"logs" validate:"required"`
-	Events    []Event      `json:"events" bson:"events"`
-	Fee       []types.Coin `json:"fee" bson:"fee"`
-	GasUsed   int64        `json:"gas_used,omitempty" bson:"gas_used,omitempty"`
-	GasWanted int64        `json:"gas_wanted,omitempty" bson:"gas_wanted,omitempty"`
-	Time      time.Time    `json:"time" bson:"time" validate:"required"`
+	Events    []Event   `json:"events" bson:"events"`
+	Fee       []Coin    `json:"fee" bson:"fee"`
+	GasUsed   int64     `json:"gas_used,omitempty" bson:"gas_used,omitempty"`
+	GasWanted int64     `json:"gas_wanted,omitempty" bson:"gas_wanted,omitempty"`
+	Time      time.Time `json:"time" bson:"time" validate:"required"`
 }
 
 func (b *Transaction) Validate() error {
@@ -35,8 +34,11 @@ type TransactionEvents struct {
 }
 
 type TransactionFilter struct {
-	Id   *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	Hash *string             `json:"hash,omitempty" bson:"hash,omitempty"`
+	Id      *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height  *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	Hash    *string             `json:"hash,omitempty" bson:"hash,omitempty"`
+	Code    *int                `json:"code,omitempty" bson:"code,omitempty"`
 }
 
 func (tf *TransactionFilter) Validate() error {
@@ -0,0 +1,107 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The topics are named after the collection of the published documents, so
+// that the documents read from a change stream are published on the same
+// topics as the ones published by the indexer.
+const (
+	TopicBlocks          = "blocks"
+	TopicSwaps           = "swaps"
+	TopicLiquidityEvents = "liquidity_events"
+	TopicFantokens       = "fantokens"
+)
+
+// Topics are the topics served to the GraphQL subscriptions.
+var Topics = []string{TopicBlocks, TopicSwaps, TopicLiquidityEvents, TopicFantokens}
+
+// subscriptionBuffer is the number of events a subscriber can lag behind
+// before it starts missing events.
+const subscriptionBuffer = 64
+
+// Bus is an in-process event bus, the events are the bson documents written
+// by the indexer and the syncs.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan bson.Raw]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string]map[chan bson.Raw]struct{})}
+}
+
+// Publish sends doc to the subscribers of topic. The publisher is never
+// blocked, a subscriber that is not keeping up misses the event.
+func (b *Bus) Publish(topic string, doc interface{}) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.subscribers[topic]) == 0 {
+		return
+	}
+
+	raw, ok := doc.(bson.Raw)
+	if !ok {
+		bz, err := bson.Marshal(doc)
+		if err != nil {
+			log.Printf("pubsub: failed to marshal %s event. err: %v", topic, err)
+			return
+		}
+		raw = bz
+	}
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- raw:
+		default:
+			log.Printf("pubsub: slow %s subscriber, event dropped", topic)
+		}
+	}
+}
+
+// Subscribe returns the events published on topic, the channel is closed
+// when ctx is done.
+func (b *Bus) Subscribe(ctx context.Context, topic string) <-chan bson.Raw {
+	ch := make(chan bson.Raw, subscriptionBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[chan bson.Raw]struct{})
+	}
+	b.subscribers[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers[topic], ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
+
+var defaultBus = NewBus()
+
+// Default returns the bus of the process.
+func Default() *Bus {
+	return defaultBus
+}
+
+// Publish sends doc to the subscribers of topic on the bus of the process.
+func Publish(topic string, doc interface{}) {
+	defaultBus.Publish(topic, doc)
+}
+
+// Subscribe subscribes to topic on the bus of the process.
+func Subscribe(ctx context.Context, topic string) <-chan bson.Raw {
+	return defaultBus.Subscribe(ctx, topic)
+}
@@ -0,0 +1,47 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	swaps := bus.Subscribe(ctx, TopicSwaps)
+
+	bus.Publish(TopicBlocks, bson.M{"height": int64(1)})
+	bus.Publish(TopicSwaps, bson.M{"pool_id": int64(1)})
+
+	select {
+	case doc := <-swaps:
+		if poolID := doc.Lookup("pool_id").Int64(); poolID != 1 {
+			t.Fatalf("expected pool 1, got %d", poolID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("swap event not received")
+	}
+
+	// a subscriber that is not reading must not block the publisher
+	for i := 0; i < subscriptionBuffer*2; i++ {
+		bus.Publish(TopicSwaps, bson.M{"pool_id": int64(i)})
+	}
+
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-swaps:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription not closed")
+		}
+	}
+}
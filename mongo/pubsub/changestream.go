@@ -0,0 +1,81 @@
+package pubsub
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const watchRetryDelay = 5 * time.Second
+
+type changeEvent struct {
+	FullDocument bson.Raw `bson:"fullDocument"`
+	Ns           struct {
+		Coll string `bson:"coll"`
+	} `bson:"ns"`
+}
+
+// Watch publishes on the bus the documents inserted in the collections of
+// database, each one on the topic named after its collection. It feeds the
+// bus when the indexer runs in another process, change streams require a
+// replica set.
+// Watch returns an error if the change stream can not be opened, once opened
+// it is resumed after any failure until ctx is done.
+func (b *Bus) Watch(ctx context.Context, database *mongo.Database, collections ...string) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "operationType", Value: "insert"},
+			{Key: "ns.coll", Value: bson.D{{Key: "$in", Value: collections}}},
+		}}},
+	}
+
+	var resumeToken bson.Raw
+	opened := false
+
+	for {
+		opts := options.ChangeStream()
+		if resumeToken != nil {
+			opts.SetResumeAfter(resumeToken)
+		}
+
+		stream, err := database.Watch(ctx, pipeline, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !opened {
+				return err
+			}
+
+			log.Printf("pubsub: failed to resume the change stream. err: %v", err)
+			time.Sleep(watchRetryDelay)
+			continue
+		}
+		opened = true
+
+		for stream.Next(ctx) {
+			var event changeEvent
+			if err := stream.Decode(&event); err != nil {
+				log.Printf("pubsub: failed to decode change event. err: %v", err)
+				continue
+			}
+
+			b.Publish(event.Ns.Coll, event.FullDocument)
+			resumeToken = stream.ResumeToken()
+		}
+
+		err = stream.Err()
+		stream.Close(context.Background())
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		log.Printf("pubsub: change stream interrupted, resuming. err: %v", err)
+		time.Sleep(watchRetryDelay)
+	}
+}
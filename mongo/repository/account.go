@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	accountCollectionName = "accounts"
+	accountDbRefName      = "default"
+)
+
+type accountRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type AccountRepository interface {
+	Count(filter *modelv2.AccountFilter) (int64, error)
+	Find(filter *modelv2.AccountFilter, pagination *types.PaginationReq) ([]*modelv2.Account, error)
+	FindOne(filter *modelv2.AccountFilter) *modelv2.Account
+	EnsureIndexes() (string, error)
+
+	FindByID(id primitive.ObjectID) *modelv2.Account
+	FindByAddress(address string) *modelv2.Account
+}
+
+func NewAccountRepository() AccountRepository {
+	coll := db.GetCollection(accountCollectionName, accountDbRefName)
+	ctx := context.Background()
+
+	return &accountRepository{context: ctx, collection: coll}
+}
+
+func (a *accountRepository) FindOne(filter *modelv2.AccountFilter) *modelv2.Account {
+	var account modelv2.Account
+	a.collection.FindOne(a.context, &filter).Decode(&account)
+
+	return &account
+}
+
+func (a *accountRepository) FindByID(id primitive.ObjectID) *modelv2.Account {
+	return a.FindOne(&modelv2.AccountFilter{Id: &id})
+}
+
+func (a *accountRepository) FindByAddress(address string) *modelv2.Account {
+	return a.FindOne(&modelv2.AccountFilter{Address: &address})
+}
+
+func (a *accountRepository) Find(filter *modelv2.AccountFilter, pagination *types.PaginationReq) ([]*modelv2.Account, error) {
+	var accounts []*modelv2.Account
+
+	var queryFilter interface{} = bson.M{}
+	if filter != nil {
+		queryFilter = filter
+	}
+
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "first_seen", -1))
+	if err != nil {
+		return accounts, err
+	}
+
+	cursor, err := a.collection.Find(a.context, query, options)
+	if err != nil {
+		return accounts, err
+	}
+	err = cursor.All(a.context, &accounts)
+	if err != nil {
+		return accounts, err
+	}
+
+	return accounts, nil
+}
+
+func (a *accountRepository) Count(filter *modelv2.AccountFilter) (int64, error) {
+	return a.collection.CountDocuments(a.context, &filter)
+}
+
+func (a *accountRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "address", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	a.collection.Indexes().CreateOne(a.context, mongo.IndexModel{
+		Keys:    bson.D{{Key: "address_key", Value: 1}},
+		Options: options.Index().SetUnique(false),
+	})
+	a.collection.Indexes().CreateMany(a.context, sortIndexes("first_seen"))
+
+	return a.collection.Indexes().CreateOne(a.context, index)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	assetCollectionName = "assets"
+	assetDbRefName      = "default"
+)
+
+type assetRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type AssetRepository interface {
+	Find(filter *modelv2.AssetFilter) ([]*modelv2.Asset, error)
+	FindOne(filter *modelv2.AssetFilter) *modelv2.Asset
+	EnsureIndexes() (string, error)
+
+	FindByDenom(chainID, denom string) *modelv2.Asset
+	Upsert(data *modelv2.Asset) error
+
+	Registry(chainID string) (*modelv2.AssetRegistry, error)
+}
+
+func NewAssetRepository() AssetRepository {
+	coll := db.GetCollection(assetCollectionName, assetDbRefName)
+	ctx := context.Background()
+
+	repo := &assetRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+func (e *assetRepository) FindOne(filter *modelv2.AssetFilter) *modelv2.Asset {
+	var asset modelv2.Asset
+	e.collection.FindOne(e.context, &filter).Decode(&asset)
+
+	return &asset
+}
+
+func (e *assetRepository) FindByDenom(chainID, denom string) *modelv2.Asset {
+	return e.FindOne(&modelv2.AssetFilter{ChainID: &chainID, Denom: &denom})
+}
+
+func (e *assetRepository) Find(filter *modelv2.AssetFilter) ([]*modelv2.Asset, error) {
+	var assets []*modelv2.Asset
+
+	var queryFilter interface{} = bson.M{}
+	if filter != nil {
+		queryFilter = filter
+	}
+
+	cursor, err := e.collection.Find(e.context, queryFilter, options.Find().SetSort(bson.M{"quote_priority": -1}))
+	if err != nil {
+		return assets, err
+	}
+	err = cursor.All(e.context, &assets)
+	if err != nil {
+		return assets, err
+	}
+
+	return assets, nil
+}
+
+func (e *assetRepository) Upsert(data *modelv2.Asset) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	filter := bson.M{"chain_id": data.ChainID, "denom": data.Denom}
+	update := bson.M{"$set": bson.M{
+		"symbol":         data.Symbol,
+		"decimals":       data.Decimals,
+		"coingecko_id":   data.CoingeckoID,
+		"quote_priority": data.QuotePriority,
+	}}
+
+	_, err := e.collection.UpdateOne(e.context, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Registry returns the registry of the assets stored for chainID.
+func (e *assetRepository) Registry(chainID string) (*modelv2.AssetRegistry, error) {
+	assets, err := e.Find(&modelv2.AssetFilter{ChainID: &chainID})
+	if err != nil {
+		return nil, err
+	}
+
+	return modelv2.NewAssetRegistry(assets...), nil
+}
+
+func (e *assetRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "chain_id", Value: 1},
+			{Key: "denom", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	return e.collection.Indexes().CreateOne(e.context, index)
+}
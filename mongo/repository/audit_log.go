@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	auditLogCollectionName = "audit_logs"
+	auditLogDbRefName      = "default"
+)
+
+type auditLogRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+// AuditLogRepository stores the changes made by the authenticated mutations.
+type AuditLogRepository interface {
+	Create(log *modelv2.AuditLog) error
+	SetOutcome(log *modelv2.AuditLog) error
+	FindByTarget(target, targetID string, limit int64) ([]*modelv2.AuditLog, error)
+	EnsureIndexes() ([]string, error)
+}
+
+func NewAuditLogRepository() AuditLogRepository {
+	coll := db.GetCollection(auditLogCollectionName, auditLogDbRefName)
+	ctx := context.Background()
+
+	repo := &auditLogRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+func (e *auditLogRepository) Create(log *modelv2.AuditLog) error {
+	if log.Time.IsZero() {
+		log.Time = time.Now()
+	}
+	log.Time = log.Time.UTC()
+
+	res, err := e.collection.InsertOne(e.context, log)
+	if err != nil {
+		return err
+	}
+
+	log.ID = res.InsertedID.(primitive.ObjectID)
+
+	return nil
+}
+
+// SetOutcome stores the status, the error and the changes of log once the
+// change is done.
+func (e *auditLogRepository) SetOutcome(log *modelv2.AuditLog) error {
+	update := bson.M{"$set": bson.M{"status": log.Status, "error": log.Error, "changes": log.Changes}}
+
+	_, err := e.collection.UpdateByID(e.context, log.ID, update)
+	return err
+}
+
+// FindByTarget returns the last changes of a resource, the newest first.
+func (e *auditLogRepository) FindByTarget(target, targetID string, limit int64) ([]*modelv2.AuditLog, error) {
+	filter := bson.D{{Key: "target", Value: target}, {Key: "target_id", Value: targetID}}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: -1}}).SetLimit(limit)
+
+	cursor, err := e.collection.Find(e.context, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]*modelv2.AuditLog, 0)
+	if err := cursor.All(e.context, &logs); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (e *auditLogRepository) EnsureIndexes() ([]string, error) {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "target", Value: 1}, {Key: "target_id", Value: 1}, {Key: "time", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "actor", Value: 1}, {Key: "time", Value: -1}},
+		},
+	}
+
+	return e.collection.Indexes().CreateMany(e.context, indexes)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/utility"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	authNonceCollectionName = "auth_nonces"
+	authNonceDbRefName      = "default"
+)
+
+type authNonceRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+// AuthNonceRepository is the nonce store of the web3tokens shared by all the
+// server processes.
+type AuthNonceRepository interface {
+	Issue(ttl time.Duration) (string, time.Time, error)
+	Consume(nonce, token string, expireAt time.Time) error
+	EnsureIndexes() ([]string, error)
+}
+
+func NewAuthNonceRepository() AuthNonceRepository {
+	coll := db.GetCollection(authNonceCollectionName, authNonceDbRefName)
+	ctx := context.Background()
+
+	repo := &authNonceRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+// Issue stores a new random nonce valid for ttl.
+func (e *authNonceRepository) Issue(ttl time.Duration) (string, time.Time, error) {
+	value, err := utility.NewNonce()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	nonce := &modelv2.AuthNonce{
+		Nonce:    value,
+		ExpireAt: time.Now().Add(ttl).UTC(),
+	}
+	if _, err := e.collection.InsertOne(e.context, nonce); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return nonce.Nonce, nonce.ExpireAt, nil
+}
+
+// Consume binds an unused and unexpired nonce to token until expireAt,
+// atomically so that a nonce is accepted for one token only. The nonce is
+// accepted again for the same token. It fails with utility.ErrInvalidNonce
+// when the nonce can not be used.
+func (e *authNonceRepository) Consume(nonce, token string, expireAt time.Time) error {
+	now := time.Now().UTC()
+
+	filter := bson.M{
+		"nonce":     nonce,
+		"expire_at": bson.M{"$gt": now},
+		"$or": bson.A{
+			bson.M{"used_at": bson.M{"$exists": false}},
+			bson.M{"token": token},
+		},
+	}
+	// the first use is kept, the nonce expires with the token
+	update := bson.A{bson.M{"$set": bson.M{
+		"token":     token,
+		"used_at":   bson.M{"$ifNull": bson.A{"$used_at", now}},
+		"expire_at": bson.M{"$max": bson.A{"$expire_at", expireAt.UTC()}},
+	}}}
+
+	err := e.collection.FindOneAndUpdate(e.context, filter, update).Err()
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return utility.ErrInvalidNonce
+	}
+
+	return err
+}
+
+func (e *authNonceRepository) EnsureIndexes() ([]string, error) {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "nonce", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// the expired nonces are removed by mongo
+			Keys:    bson.D{{Key: "expire_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	return e.collection.Indexes().CreateMany(e.context, indexes)
+}
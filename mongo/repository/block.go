@@ -33,6 +33,7 @@ type BlockRepository interface {
 	FindByHeight(height int64) *modelv2.Block
 
 	Create(data *types.BlockCreateReq) (*modelv2.Block, error)
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 
 	Earliest() *modelv2.Block
 	Latest() *modelv2.Block
@@ -151,6 +152,15 @@ func (b *blockRepository) Latest() *modelv2.Block {
 	return &block
 }
 
+func (b *blockRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := b.collection.DeleteMany(b.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 func (b *blockRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
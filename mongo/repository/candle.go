@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	candleCollectionName = "candles"
+	candleDbRefName      = "default"
+)
+
+type candleRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type CandleRepository interface {
+	Find(chainID string, poolID uint64, interval modelv2.CandleInterval, from, to time.Time) ([]*modelv2.Candle, error)
+	EnsureIndexes() ([]string, error)
+
+	UpsertMany(candles []*modelv2.Candle) error
+	DeleteStale(chainID string, interval modelv2.CandleInterval, from, to time.Time, height int64) (int64, error)
+}
+
+func NewCandleRepository() CandleRepository {
+	coll := db.GetCollection(candleCollectionName, candleDbRefName)
+	ctx := context.Background()
+
+	repo := &candleRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+// Find returns the candles of the pool opened between from and to, sorted by
+// time.
+func (e *candleRepository) Find(chainID string, poolID uint64, interval modelv2.CandleInterval, from, to time.Time) ([]*modelv2.Candle, error) {
+	var candles []*modelv2.Candle
+
+	filter := bson.M{
+		"meta.chain_id": chainID,
+		"meta.pool_id":  poolID,
+		"meta.interval": interval,
+		"time":          bson.M{"$gte": from, "$lte": to},
+	}
+
+	cursor, err := e.collection.Find(e.context, filter, options.Find().SetSort(bson.D{{Key: "time", Value: 1}}))
+	if err != nil {
+		return candles, err
+	}
+	err = cursor.All(e.context, &candles)
+	if err != nil {
+		return candles, err
+	}
+
+	return candles, nil
+}
+
+// UpsertMany replaces the stored candles of the same pool, interval and time,
+// the candles not stored yet are inserted.
+func (e *candleRepository) UpsertMany(candles []*modelv2.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	models := make([]mongo.WriteModel, len(candles))
+	for i, candle := range candles {
+		filter := bson.M{
+			"meta.chain_id": candle.Meta.ChainID,
+			"meta.pool_id":  candle.Meta.PoolID,
+			"meta.interval": candle.Meta.Interval,
+			"time":          candle.Time,
+		}
+
+		// the _id of the stored candle is kept
+		replacement := *candle
+		replacement.ID = primitive.NilObjectID
+
+		models[i] = mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(&replacement).SetUpsert(true)
+	}
+
+	_, err := e.collection.BulkWrite(e.context, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// DeleteStale removes the candles of the chain and interval opened between
+// from and to that were not built at height, they are the candles of the
+// intervals left without swaps after a rollback.
+func (e *candleRepository) DeleteStale(chainID string, interval modelv2.CandleInterval, from, to time.Time, height int64) (int64, error) {
+	filter := bson.M{
+		"meta.chain_id": chainID,
+		"meta.interval": interval,
+		"time":          bson.M{"$gte": from, "$lt": to},
+		"height":        bson.M{"$ne": height},
+	}
+
+	res, err := e.collection.DeleteMany(e.context, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+func (e *candleRepository) EnsureIndexes() ([]string, error) {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "meta.chain_id", Value: 1},
+				{Key: "meta.pool_id", Value: 1},
+				{Key: "meta.interval", Value: 1},
+				{Key: "time", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "meta.chain_id", Value: 1},
+				{Key: "meta.interval", Value: 1},
+				{Key: "time", Value: 1},
+			},
+		},
+	}
+
+	return e.collection.Indexes().CreateMany(e.context, indexes)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	checkpointCollectionName = "checkpoints"
+	checkpointDbRefName      = "default"
+)
+
+// ErrCheckpointConflict is returned when the checkpoint has been moved by
+// another process since it was read.
+var ErrCheckpointConflict = errors.New("checkpoint has been updated by another process")
+
+type checkpointRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type CheckpointRepository interface {
+	Find(filter *modelv2.CheckpointFilter) ([]*modelv2.Checkpoint, error)
+	EnsureIndexes() (string, error)
+
+	Get(chainID, module string) *modelv2.Checkpoint
+	CompareAndSet(chainID, module string, expected, height int64) error
+	Save(checkpoint *modelv2.Checkpoint, height int64) error
+	Rewind(chainID string, height int64) (int64, error)
+}
+
+func NewCheckpointRepository() CheckpointRepository {
+	coll := db.GetCollection(checkpointCollectionName, checkpointDbRefName)
+	ctx := context.Background()
+
+	repo := &checkpointRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+func (e *checkpointRepository) Find(filter *modelv2.CheckpointFilter) ([]*modelv2.Checkpoint, error) {
+	var checkpoints []*modelv2.Checkpoint
+
+	opts := options.Find().SetSort(bson.D{{Key: "chain_id", Value: 1}, {Key: "module", Value: 1}})
+
+	var queryFilter interface{} = bson.M{}
+	if filter != nil {
+		queryFilter = filter
+	}
+
+	cursor, err := e.collection.Find(e.context, queryFilter, opts)
+	if err != nil {
+		return checkpoints, err
+	}
+	err = cursor.All(e.context, &checkpoints)
+	if err != nil {
+		return checkpoints, err
+	}
+
+	return checkpoints, nil
+}
+
+// Get returns the checkpoint of the module, a new checkpoint at height 0 is
+// returned when the module has never been synced.
+func (e *checkpointRepository) Get(chainID, module string) *modelv2.Checkpoint {
+	checkpoint := modelv2.Checkpoint{ChainID: chainID, Module: module}
+	e.collection.FindOne(e.context, &modelv2.CheckpointFilter{ChainID: &chainID, Module: &module}).Decode(&checkpoint)
+
+	return &checkpoint
+}
+
+// CompareAndSet moves the checkpoint to height only if it is still at expected.
+func (e *checkpointRepository) CompareAndSet(chainID, module string, expected, height int64) error {
+	filter := bson.M{"chain_id": chainID, "module": module, "height": expected}
+	update := bson.M{"$set": bson.M{"height": height, "updated_at": time.Now()}}
+
+	// the upsert creates the missing checkpoints, when the checkpoint exists at
+	// a different height the unique index makes the insert fail
+	_, err := e.collection.UpdateOne(e.context, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrCheckpointConflict
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Save moves the checkpoint to height and updates it on success.
+func (e *checkpointRepository) Save(checkpoint *modelv2.Checkpoint, height int64) error {
+	if err := e.CompareAndSet(checkpoint.ChainID, checkpoint.Module, checkpoint.Height, height); err != nil {
+		return err
+	}
+
+	checkpoint.Height = height
+	checkpoint.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// Rewind moves back to height every checkpoint of the chain above it.
+func (e *checkpointRepository) Rewind(chainID string, height int64) (int64, error) {
+	filter := bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}}
+	update := bson.M{"$set": bson.M{"height": height, "updated_at": time.Now()}}
+
+	res, err := e.collection.UpdateMany(e.context, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.ModifiedCount, nil
+}
+
+func (e *checkpointRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "chain_id", Value: 1},
+			{Key: "module", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	return e.collection.Indexes().CreateOne(e.context, index)
+}
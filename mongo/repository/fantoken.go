@@ -5,7 +5,6 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
 	"github.com/angelorc/sinfonia-go/mongo/types"
-	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -37,10 +36,12 @@ type FantokenRepository interface {
 
 	Earliest() *modelv2.Fantoken
 	Latest() *modelv2.Fantoken
+
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewFantokenRepository() FantokenRepository {
-	coll := db.GetCollection(blockCollectionName, blockDbRefName)
+	coll := db.GetCollection(fantokenCollectionName, fantokenDbRefName)
 	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
 
 	return &fantokenRepository{context: ctx, collection: coll}
@@ -53,27 +54,17 @@ func (f fantokenRepository) Count(filter *types.FantokenFilter) (int64, error) {
 func (f fantokenRepository) Find(filter *types.FantokenFilter, pagination *types.PaginationReq) ([]*modelv2.Fantoken, error) {
 	var fantokens []*modelv2.Fantoken
 
-	orderByKey := "height"
-	orderByValue := -1
-
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
-	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
-	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
-	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
-
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := f.collection.Find(f.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return fantokens, err
+	}
+
+	cursor, err := f.collection.Find(f.context, query, options)
 	if err != nil {
 		return fantokens, err
 	}
@@ -108,22 +99,21 @@ func (f fantokenRepository) EnsureIndexes() ([]string, error) {
 		},
 	}
 
+	indexes = append(indexes, sortIndexes("height", "issued_at")...)
+
 	return f.collection.Indexes().CreateMany(f.context, indexes)
 }
 
 func (f fantokenRepository) FindByID(id primitive.ObjectID) *modelv2.Fantoken {
-	//TODO implement me
-	panic("implement me")
+	return f.FindOne(&types.FantokenFilter{Id: &id})
 }
 
 func (f fantokenRepository) FindByHeight(height int64) *modelv2.Fantoken {
-	//TODO implement me
-	panic("implement me")
+	return f.FindOne(&types.FantokenFilter{Height: &height})
 }
 
 func (f fantokenRepository) FindByDenom(denom string) *modelv2.Fantoken {
-	//TODO implement me
-	panic("implement me")
+	return f.FindOne(&types.FantokenFilter{Denom: &denom})
 }
 
 func (f fantokenRepository) Create(data *types.FantokenCreateReq) (*modelv2.Fantoken, error) {
@@ -140,3 +130,13 @@ func (f fantokenRepository) Latest() *modelv2.Fantoken {
 	//TODO implement me
 	panic("implement me")
 }
+
+// DeleteAboveHeight removes the fantokens of chainID issued above height.
+func (f fantokenRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := f.collection.DeleteMany(f.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
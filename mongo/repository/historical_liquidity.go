@@ -23,8 +23,13 @@ type historicalLiquidityRepository struct {
 }
 
 type HistoricalLiquidityRepository interface {
+	Find(poolID uint64, from, to time.Time) ([]*modelv2.HistoricalLiquidity, error)
+	Latest(chainID string, poolID uint64) *modelv2.HistoricalLiquidity
 	EnsureIndexes() (string, error)
+
 	Create(data *modelv2.HistoricalLiquidityCreateReq) (*primitive.ObjectID, error)
+	Upsert(data *modelv2.HistoricalLiquidityCreateReq) error
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewHistoricalLiquidityRepository() HistoricalLiquidityRepository {
@@ -35,6 +40,40 @@ func NewHistoricalLiquidityRepository() HistoricalLiquidityRepository {
 	return &historicalLiquidityRepository{context: ctx, collection: coll}
 }
 
+// Find returns the snapshots of the pool taken between from and to, sorted by
+// time.
+func (e *historicalLiquidityRepository) Find(poolID uint64, from, to time.Time) ([]*modelv2.HistoricalLiquidity, error) {
+	var snapshots []*modelv2.HistoricalLiquidity
+
+	filter := bson.M{
+		"pool_id": poolID,
+		"time":    bson.M{"$gte": from, "$lte": to},
+	}
+
+	cursor, err := e.collection.Find(e.context, filter, options.Find().SetSort(bson.D{{Key: "time", Value: 1}}))
+	if err != nil {
+		return snapshots, err
+	}
+	err = cursor.All(e.context, &snapshots)
+	if err != nil {
+		return snapshots, err
+	}
+
+	return snapshots, nil
+}
+
+// Latest returns the last snapshot of the pool, an empty snapshot is returned
+// when the pool has none.
+func (e *historicalLiquidityRepository) Latest(chainID string, poolID uint64) *modelv2.HistoricalLiquidity {
+	var snapshot modelv2.HistoricalLiquidity
+
+	filter := bson.M{"chain_id": chainID, "pool_id": poolID}
+	opts := options.FindOne().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "height", Value: -1}})
+	e.collection.FindOne(e.context, filter, opts).Decode(&snapshot)
+
+	return &snapshot
+}
+
 func (e *historicalLiquidityRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -93,3 +132,27 @@ func (e *historicalLiquidityRepository) Create(data *modelv2.HistoricalLiquidity
 
 	return &insertedID, nil
 }
+
+// Upsert stores the snapshot of the pool for its interval, replacing the one
+// already stored.
+func (e *historicalLiquidityRepository) Upsert(data *modelv2.HistoricalLiquidityCreateReq) error {
+	filter := bson.M{"pool_id": data.PoolID, "time": data.Time}
+	update := bson.M{"$set": bson.M{
+		"chain_id":  data.ChainID,
+		"height":    data.Height,
+		"assets":    data.Assets,
+		"usd_value": data.UsdValue,
+	}}
+
+	_, err := e.collection.UpdateOne(e.context, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (e *historicalLiquidityRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
@@ -32,6 +32,7 @@ type HistoricalPriceRepository interface {
 
 	FindByID(id primitive.ObjectID) *modelv2.HistoricalPrice
 	FindByAsset(asset string, time time.Time) []*modelv2.HistoricalPrice
+	Lookup(asset string, t time.Time, lookup modelv2.PriceLookup) (*modelv2.PriceQuote, bool)
 
 	Create(data *modelv2.HistoricalPriceCreateReq) (*primitive.ObjectID, error)
 	InsertMany(records []interface{}) (*mongo.InsertManyResult, error)
@@ -189,6 +190,29 @@ func (e *historicalPriceRepository) FindByAsset(asset string, time time.Time) []
 	return prices
 }
 
+// Lookup returns the price of asset at time t from the samples surrounding t,
+// false is returned when there is no sample fresh enough.
+func (e *historicalPriceRepository) Lookup(asset string, t time.Time, lookup modelv2.PriceLookup) (*modelv2.PriceQuote, bool) {
+	before := e.nearest(asset, bson.M{"$lte": t}, -1)
+	after := e.nearest(asset, bson.M{"$gt": t}, 1)
+
+	return lookup.Quote(asset, before, after, t)
+}
+
+// nearest returns the first sample of asset in the time range sorted by time
+// in the order, nil when there is none.
+func (e *historicalPriceRepository) nearest(asset string, timeRange bson.M, order int) *modelv2.HistoricalPrice {
+	var hp modelv2.HistoricalPrice
+
+	filter := bson.M{"asset": asset, "time": timeRange}
+	opts := options.FindOne().SetSort(bson.D{{Key: "time", Value: order}})
+	if err := e.collection.FindOne(e.context, filter, opts).Decode(&hp); err != nil {
+		return nil
+	}
+
+	return &hp
+}
+
 func (e *historicalPriceRepository) Create(data *modelv2.HistoricalPriceCreateReq) (*primitive.ObjectID, error) {
 	data.ID = primitive.NewObjectID()
 
@@ -6,7 +6,6 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
 	"github.com/angelorc/sinfonia-go/mongo/types"
-	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -33,9 +32,12 @@ type IncentiveRepository interface {
 	FindByID(id primitive.ObjectID) *modelv2.Incentive
 	FindByHeight(height int64) *modelv2.Incentive
 	FindByReceiver(receiver string) []*modelv2.Incentive
+	SumByReceiver(chainID string, from, to time.Time) ([]*modelv2.ReceiverIncentives, error)
+	SumByReceivers(receivers []string) (*modelv2.AccountIncentives, error)
 
 	Create(data *modelv2.IncentiveCreateReq) (*primitive.ObjectID, error)
 	CreateMany(data []*modelv2.IncentiveCreateReq) (bool, error)
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewIncentiveRepository() IncentiveRepository {
@@ -64,27 +66,17 @@ func (e *incentiveRepository) FindByHeight(height int64) *modelv2.Incentive {
 func (e *incentiveRepository) Find(filter *modelv2.IncentiveFilter, pagination *types.PaginationReq) ([]*modelv2.Incentive, error) {
 	var incentives []*modelv2.Incentive
 
-	orderByKey := "height"
-	orderByValue := -1
-
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
-	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
-	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
-	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
-
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := e.collection.Find(e.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return incentives, err
+	}
+
+	cursor, err := e.collection.Find(e.context, query, options)
 	if err != nil {
 		return incentives, err
 	}
@@ -145,6 +137,72 @@ func (e *incentiveRepository) CreateMany(data []*modelv2.IncentiveCreateReq) (bo
 	return true, nil
 }
 
+// SumByReceiver returns the USD value of the incentives distributed from time
+// from (included) to time to (excluded), by receiver.
+func (e *incentiveRepository) SumByReceiver(chainID string, from, to time.Time) ([]*modelv2.ReceiverIncentives, error) {
+	var results []*modelv2.ReceiverIncentives
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"chain_id": chainID,
+			"time":     bson.M{"$gte": from, "$lt": to},
+		}},
+		{"$group": bson.M{
+			"_id":       "$receiver",
+			"usd_value": bson.M{"$sum": "$usd_value"},
+		}},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// SumByReceivers sums the incentives received by receivers.
+func (e *incentiveRepository) SumByReceivers(receivers []string) (*modelv2.AccountIncentives, error) {
+	var results []*modelv2.AccountIncentives
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"receiver": bson.M{"$in": receivers}}},
+		{"$group": bson.M{
+			"_id":       nil,
+			"count":     bson.M{"$sum": 1},
+			"usd_value": bson.M{"$sum": "$usd_value"},
+		}},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return &modelv2.AccountIncentives{}, nil
+	}
+
+	return results[0], nil
+}
+
+func (e *incentiveRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 func (e *incentiveRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -162,5 +220,7 @@ func (e *incentiveRepository) EnsureIndexes() (string, error) {
 		Options: options.Index().SetUnique(false),
 	}
 
+	e.collection.Indexes().CreateMany(e.context, sortIndexes("height", "time"))
+
 	return e.collection.Indexes().CreateOne(e.context, index)
 }
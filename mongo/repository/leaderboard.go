@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	leaderboardCollectionName = "leaderboards"
+	leaderboardDbRefName      = "default"
+)
+
+type leaderboardRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type LeaderboardRepository interface {
+	Top(filter *modelv2.LeaderboardFilter) ([]*modelv2.LeaderboardEntry, error)
+	EnsureIndexes() (string, error)
+
+	RefreshSwaps(chainID string, from, to time.Time) error
+	RefreshLiquidity(chainID string, from, to time.Time) error
+	RefreshClaims(chainID string, from, to time.Time) error
+}
+
+func NewLeaderboardRepository() LeaderboardRepository {
+	coll := db.GetCollection(leaderboardCollectionName, leaderboardDbRefName)
+	ctx := context.Background()
+
+	return &leaderboardRepository{context: ctx, collection: coll}
+}
+
+// Top ranks the accounts by the sum of the buckets matching filter. The
+// addresses of the same key are ranked together.
+func (e *leaderboardRepository) Top(filter *modelv2.LeaderboardFilter) ([]*modelv2.LeaderboardEntry, error) {
+	var entries []*modelv2.LeaderboardEntry
+
+	match := bson.M{"metric": filter.Metric, "chain_id": filter.ChainID}
+	if filter.PoolID != nil {
+		match["pool_id"] = *filter.PoolID
+	}
+	if filter.Since != nil {
+		match["day"] = bson.M{"$gte": *filter.Since}
+	}
+
+	// the addresses without a key are ranked by address
+	account := bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$account_key", ""}},
+		"$account",
+		"$account_key",
+	}}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id":         account,
+			"account":     bson.M{"$first": "$account"},
+			"account_key": bson.M{"$first": "$account_key"},
+			"value":       bson.M{"$sum": "$value"},
+			"count":       bson.M{"$sum": "$count"},
+		}},
+		{"$sort": bson.D{{Key: "value", Value: -1}, {Key: "_id", Value: 1}}},
+		{"$limit": filter.Limit},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return entries, err
+	}
+	err = cursor.All(e.context, &entries)
+	if err != nil {
+		return entries, err
+	}
+
+	for i, entry := range entries {
+		entry.Rank = i + 1
+	}
+
+	return entries, nil
+}
+
+// RefreshSwaps rebuilds the volume buckets of the days from the day of from
+// to the day of to (both included).
+func (e *leaderboardRepository) RefreshSwaps(chainID string, from, to time.Time) error {
+	return e.refresh(modelv2.LeaderboardMetricVolume, chainID, swapCollectionName, bucketSource{
+		match:   bson.M{"chain_id": chainID},
+		time:    "time",
+		pool:    "$pool_id",
+		account: "account",
+		key:     "account_key",
+		value:   "$usd_value",
+	}, from, to)
+}
+
+// RefreshLiquidity rebuilds the liquidity buckets of the days from the day of
+// from to the day of to (both included), only the joins are counted.
+func (e *leaderboardRepository) RefreshLiquidity(chainID string, from, to time.Time) error {
+	return e.refresh(modelv2.LeaderboardMetricLiquidity, chainID, liquidityEventCollectionName, bucketSource{
+		match:   bson.M{"chain_id": chainID, "type": modelv2.LiquidityEventTypeJoin},
+		time:    "time",
+		pool:    "$pool_id",
+		account: "sender",
+		key:     "sender_key",
+		value:   "$usd_value",
+	}, from, to)
+}
+
+// RefreshClaims rebuilds the claims buckets of the days from the day of from
+// to the day of to (both included). The claims synced without their block time
+// are not counted.
+func (e *leaderboardRepository) RefreshClaims(chainID string, from, to time.Time) error {
+	return e.refresh(modelv2.LeaderboardMetricClaims, chainID, merkledropProofCollectionName, bucketSource{
+		match:   bson.M{"claimed": true},
+		time:    "claimed_at",
+		pool:    bson.M{"$literal": 0},
+		account: "address",
+		key:     "address_key",
+		value:   1,
+	}, from, to)
+}
+
+// bucketSource describes how the documents of a collection are summed into
+// buckets, pool and value are aggregation expressions.
+type bucketSource struct {
+	match   bson.M
+	time    string
+	pool    interface{}
+	account string
+	key     string
+	value   interface{}
+}
+
+// refresh replaces the buckets of metric of the days from the day of from to
+// the day of to with the ones built from the source collection, the days are
+// replaced as a whole so that they can be synced again.
+func (e *leaderboardRepository) refresh(metric modelv2.LeaderboardMetric, chainID, collectionName string, source bucketSource, from, to time.Time) error {
+	fromDay := modelv2.LeaderboardDay(from)
+	toDay := modelv2.LeaderboardDay(to).Add(24 * time.Hour)
+
+	_, err := e.collection.DeleteMany(e.context, bson.M{
+		"metric":   metric,
+		"chain_id": chainID,
+		"day":      bson.M{"$gte": fromDay, "$lt": toDay},
+	})
+	if err != nil {
+		return err
+	}
+
+	match := bson.M{source.time: bson.M{"$gte": fromDay, "$lt": toDay}}
+	for k, v := range source.match {
+		match[k] = v
+	}
+
+	timeField := "$" + source.time
+	day := bson.M{"$dateFromParts": bson.M{
+		"year":  bson.M{"$year": timeField},
+		"month": bson.M{"$month": timeField},
+		"day":   bson.M{"$dayOfMonth": timeField},
+	}}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"pool_id": source.pool,
+				"account": "$" + source.account,
+				"day":     day,
+			},
+			"account_key": bson.M{"$first": "$" + source.key},
+			"value":       bson.M{"$sum": source.value},
+			"count":       bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":         0,
+			"chain_id":    bson.M{"$literal": chainID},
+			"metric":      bson.M{"$literal": metric},
+			"pool_id":     "$_id.pool_id",
+			"account":     "$_id.account",
+			"account_key": bson.M{"$ifNull": bson.A{"$account_key", ""}},
+			"day":         "$_id.day",
+			"value":       1,
+			"count":       1,
+		}},
+		{"$merge": bson.M{
+			"into":           leaderboardCollectionName,
+			"on":             bson.A{"metric", "chain_id", "pool_id", "account", "day"},
+			"whenMatched":    "replace",
+			"whenNotMatched": "insert",
+		}},
+	}
+
+	cursor, err := db.GetCollection(collectionName, leaderboardDbRefName).Aggregate(e.context, pipeline)
+	if err != nil {
+		return err
+	}
+
+	return cursor.Close(e.context)
+}
+
+func (e *leaderboardRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "metric", Value: 1},
+			{Key: "chain_id", Value: 1},
+			{Key: "day", Value: -1},
+			{Key: "pool_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	// $merge requires a unique index on the merge fields
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "metric", Value: 1},
+			{Key: "chain_id", Value: 1},
+			{Key: "pool_id", Value: 1},
+			{Key: "account", Value: 1},
+			{Key: "day", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	return e.collection.Indexes().CreateOne(e.context, index)
+}
@@ -33,8 +33,16 @@ type LiquidityRepository interface {
 	FindByID(id primitive.ObjectID) *modelv2.LiquidityEvent
 	FindByHeight(height int64) []*modelv2.LiquidityEvent
 	FindBySender(sender string) []*modelv2.LiquidityEvent
+	FindByHeightRange(chainID string, fromHeight, toHeight int64) ([]*modelv2.LiquidityEvent, error)
+	Balances(chainID string, senders []string) ([]*modelv2.LiquidityBalance, error)
+	SumBySenders(senders []string) (*modelv2.AccountLiquidity, error)
+
+	FindUnvalued(chainID string, limit int64) ([]*modelv2.LiquidityEvent, error)
 
 	Create(data *modelv2.LiquidityEventCreateReq) (*primitive.ObjectID, error)
+	SetMissingTypes(chainID string) (int64, error)
+	SetUsdValue(id primitive.ObjectID, usdValue float64) error
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewLiquidityRepository() LiquidityRepository {
@@ -61,30 +69,129 @@ func (e *liquidityEventRepository) FindByHeight(height int64) []*modelv2.Liquidi
 	return results
 }
 
-func (e *liquidityEventRepository) Find(filter *modelv2.LiquidityEventFilter, pagination *types.PaginationReq) ([]*modelv2.LiquidityEvent, error) {
+// FindByHeightRange returns the liquidity events of the chain from height
+// fromHeight to height toHeight (both included), sorted by height.
+func (e *liquidityEventRepository) FindByHeightRange(chainID string, fromHeight, toHeight int64) ([]*modelv2.LiquidityEvent, error) {
 	var results []*modelv2.LiquidityEvent
 
-	orderByKey := "height"
-	orderByValue := -1
+	filter := bson.M{
+		"chain_id": chainID,
+		"height":   bson.M{"$gte": fromHeight, "$lte": toHeight},
+	}
+
+	cursor, err := e.collection.Find(e.context, filter, options.Find().SetSort(bson.D{{Key: "height", Value: 1}}))
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// SumBySenders sums the joins and the exits of senders.
+func (e *liquidityEventRepository) SumBySenders(senders []string) (*modelv2.AccountLiquidity, error) {
+	var results []*modelv2.AccountLiquidity
+
+	isType := func(eventType string) bson.M {
+		return bson.M{"$eq": bson.A{"$type", eventType}}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"sender": bson.M{"$in": senders}}},
+		{"$group": bson.M{
+			"_id":       nil,
+			"joins":     bson.M{"$sum": bson.M{"$cond": bson.A{isType(modelv2.LiquidityEventTypeJoin), 1, 0}}},
+			"exits":     bson.M{"$sum": bson.M{"$cond": bson.A{isType(modelv2.LiquidityEventTypeExit), 1, 0}}},
+			"deposited": bson.M{"$sum": bson.M{"$cond": bson.A{isType(modelv2.LiquidityEventTypeJoin), "$usd_value", 0}}},
+			"withdrawn": bson.M{"$sum": bson.M{"$cond": bson.A{isType(modelv2.LiquidityEventTypeExit), "$usd_value", 0}}},
+		}},
+	}
 
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return nil, err
 	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return nil, err
 	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
+
+	if len(results) == 0 {
+		return &modelv2.AccountLiquidity{}, nil
 	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
+
+	return results[0], nil
+}
+
+// Balances returns the amounts joined minus the amounts exited by senders, by
+// sender, pool and denom.
+func (e *liquidityEventRepository) Balances(chainID string, senders []string) ([]*modelv2.LiquidityBalance, error) {
+	var results []*modelv2.LiquidityBalance
+
+	tokens := func(field string, sign int) bson.M {
+		return bson.M{"$map": bson.M{
+			"input": field,
+			"as":    "t",
+			"in": bson.M{
+				"denom":  "$$t.denom",
+				"amount": bson.M{"$multiply": bson.A{"$$t.amount_dec", sign}},
+			},
+		}}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"chain_id": chainID,
+			"sender":   bson.M{"$in": senders},
+		}},
+		{"$project": bson.M{
+			"sender":  1,
+			"pool_id": 1,
+			"tokens":  bson.M{"$concatArrays": bson.A{tokens("$tokens_in", 1), tokens("$tokens_out", -1)}},
+		}},
+		{"$unwind": "$tokens"},
+		{"$group": bson.M{
+			"_id":    bson.M{"sender": "$sender", "pool_id": "$pool_id", "denom": "$tokens.denom"},
+			"amount": bson.M{"$sum": "$tokens.amount"},
+		}},
+		{"$project": bson.M{
+			"_id":     0,
+			"sender":  "$_id.sender",
+			"pool_id": "$_id.pool_id",
+			"denom":   "$_id.denom",
+			"amount":  bson.M{"$toDecimal": "$amount"},
+		}},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+func (e *liquidityEventRepository) Find(filter *modelv2.LiquidityEventFilter, pagination *types.PaginationReq) ([]*modelv2.LiquidityEvent, error) {
+	var results []*modelv2.LiquidityEvent
 
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := e.collection.Find(e.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return results, err
+	}
+
+	cursor, err := e.collection.Find(e.context, query, options)
 	if err != nil {
 		return results, err
 	}
@@ -107,6 +214,7 @@ func (e *liquidityEventRepository) Count(filter *modelv2.LiquidityEventFilter) (
 
 func (e *liquidityEventRepository) Create(data *modelv2.LiquidityEventCreateReq) (*primitive.ObjectID, error) {
 	data.ID = primitive.NewObjectID()
+	data.SenderKey, _ = utility.AddressKey(data.Sender)
 
 	if err := data.Validate(); err != nil {
 		return &primitive.ObjectID{}, err
@@ -125,6 +233,66 @@ func (e *liquidityEventRepository) Create(data *modelv2.LiquidityEventCreateReq)
 	return &insertedID, nil
 }
 
+// FindUnvalued returns the events stored before the USD value was computed on
+// sync.
+func (e *liquidityEventRepository) FindUnvalued(chainID string, limit int64) ([]*modelv2.LiquidityEvent, error) {
+	var results []*modelv2.LiquidityEvent
+
+	filter := bson.M{"chain_id": chainID, "usd_value": bson.M{"$exists": false}}
+
+	cursor, err := e.collection.Find(e.context, filter, options.Find().SetLimit(limit))
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// SetMissingTypes sets the type of the events stored before the type was set
+// on sync, a join has tokens in and an exit tokens out.
+func (e *liquidityEventRepository) SetMissingTypes(chainID string) (int64, error) {
+	missing := bson.M{"$in": bson.A{nil, ""}}
+
+	byTokens := []struct {
+		eventType string
+		tokens    string
+	}{
+		{modelv2.LiquidityEventTypeJoin, "tokens_in.0"},
+		{modelv2.LiquidityEventTypeExit, "tokens_out.0"},
+	}
+
+	updated := int64(0)
+	for _, t := range byTokens {
+		filter := bson.M{"chain_id": chainID, "type": missing, t.tokens: bson.M{"$exists": true}}
+		res, err := e.collection.UpdateMany(e.context, filter, bson.M{"$set": bson.M{"type": t.eventType}})
+		if err != nil {
+			return updated, err
+		}
+
+		updated += res.ModifiedCount
+	}
+
+	return updated, nil
+}
+
+func (e *liquidityEventRepository) SetUsdValue(id primitive.ObjectID, usdValue float64) error {
+	_, err := e.collection.UpdateOne(e.context, bson.M{"_id": id}, bson.M{"$set": bson.M{"usd_value": usdValue}})
+	return err
+}
+
+func (e *liquidityEventRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 func (e *liquidityEventRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -144,6 +312,27 @@ func (e *liquidityEventRepository) EnsureIndexes() (string, error) {
 
 	e.collection.Indexes().CreateOne(e.context, index)
 
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sender_key", Value: 1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "pool_id", Value: 1},
+			{Key: "time", Value: -1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	e.collection.Indexes().CreateMany(e.context, sortIndexes("height", "time"))
+
 	index = mongo.IndexModel{
 		Keys: bson.D{
 			{"height", 1},
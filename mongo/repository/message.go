@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// the messages are not stored in their own collection, they are read from the
+// "message" events of the transactions
+const msgEventType = "message"
+
+type messageRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type MessageRepository interface {
+	Count(filter *modelv2.MessageFilter) (int64, error)
+	Find(filter *modelv2.MessageFilter, pagination *types.PaginationReq) ([]*modelv2.Message, error)
+	FindOne(filter *modelv2.MessageFilter) *modelv2.Message
+}
+
+func NewMessageRepository() MessageRepository {
+	coll := db.GetCollection(transactionCollectionName, transactionDbRefName)
+	ctx := context.Background()
+
+	return &messageRepository{context: ctx, collection: coll}
+}
+
+func (m *messageRepository) FindOne(filter *modelv2.MessageFilter) *modelv2.Message {
+	limit := int64(1)
+
+	messages, err := m.Find(filter, &types.PaginationReq{Limit: &limit})
+	if err != nil || len(messages) == 0 {
+		return &modelv2.Message{}
+	}
+
+	return messages[0]
+}
+
+func (m *messageRepository) Find(filter *modelv2.MessageFilter, pagination *types.PaginationReq) ([]*modelv2.Message, error) {
+	var messages []*modelv2.Message
+
+	txKeys := sortKeys(pagination, "height", -1)
+	// the messages of a transaction keep the order of the events
+	msgKeys := []sortKey{txKeys[0], {name: "tx_id", order: txKeys[1].order}, {name: "msg_index", order: 1}}
+
+	var txSeek, msgSeek bson.M
+	if pagination.After != nil {
+		cursor, err := decodeCursor(*pagination.After, msgKeys)
+		if err != nil {
+			return messages, err
+		}
+
+		msgSeek, err = seekFilter(cursor.Values, msgKeys)
+		if err != nil {
+			return messages, err
+		}
+
+		// the transaction of the cursor is matched again, its messages
+		// following the cursor are left by the seek on the messages
+		txCursorSeek, err := seekFilter(cursor.Values[:2], txKeys)
+		if err != nil {
+			return messages, err
+		}
+		txSeek = bson.M{"$or": bson.A{txCursorSeek, bson.M{"_id": cursor.Values[1]}}}
+	}
+
+	pipeline := m.pipeline(filter, sortDoc(txKeys), txSeek, msgSeek)
+	if pagination.Skip != nil {
+		pipeline = append(pipeline, bson.M{"$skip": *pagination.Skip})
+	}
+	if pagination.Limit != nil {
+		pipeline = append(pipeline, bson.M{"$limit": *pagination.Limit})
+	}
+
+	cursor, err := m.collection.Aggregate(m.context, pipeline)
+	if err != nil {
+		return messages, err
+	}
+	err = cursor.All(m.context, &messages)
+	if err != nil {
+		return messages, err
+	}
+
+	return messages, nil
+}
+
+func (m *messageRepository) Count(filter *modelv2.MessageFilter) (int64, error) {
+	pipeline := append(m.pipeline(filter, nil, nil, nil), bson.M{"$count": "count"})
+
+	cursor, err := m.collection.Aggregate(m.context, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var res []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(m.context, &res); err != nil {
+		return 0, err
+	}
+
+	if len(res) == 0 {
+		return 0, nil
+	}
+
+	return res[0].Count, nil
+}
+
+// pipeline matches the transactions first, so that the indexes on the
+// transactions are used, then unwinds the message events. txSeek and msgSeek
+// are the seek filters of the transactions and of the messages of a page.
+func (m *messageRepository) pipeline(filter *modelv2.MessageFilter, sort bson.D, txSeek, msgSeek bson.M) []bson.M {
+	txMatch := bson.M{"events.type": msgEventType}
+	msgMatch := bson.M{}
+	values := bson.A{}
+
+	if filter != nil {
+		if filter.ChainID != nil {
+			txMatch["chain_id"] = *filter.ChainID
+		}
+		if filter.Height != nil {
+			txMatch["height"] = *filter.Height
+		}
+		if filter.TxHash != nil {
+			txMatch["hash"] = *filter.TxHash
+		}
+		if filter.MsgIndex != nil {
+			msgMatch["msg_index"] = *filter.MsgIndex
+		}
+		if filter.MsgType != nil {
+			values = append(values, *filter.MsgType)
+			msgMatch["msg_type"] = *filter.MsgType
+		}
+		if filter.Signer != nil {
+			values = append(values, *filter.Signer)
+			msgMatch["signer"] = *filter.Signer
+		}
+	}
+
+	if len(values) > 0 {
+		txMatch["events.attributes.value"] = bson.M{"$all": values}
+	}
+
+	if txSeek != nil {
+		txMatch["$and"] = bson.A{txSeek}
+	}
+	if msgSeek != nil {
+		msgMatch["$and"] = bson.A{msgSeek}
+	}
+
+	pipeline := []bson.M{{"$match": txMatch}}
+	if sort != nil {
+		pipeline = append(pipeline, bson.M{"$sort": sort})
+	}
+
+	return append(pipeline,
+		bson.M{"$unwind": "$events"},
+		bson.M{"$match": bson.M{"events.type": msgEventType}},
+		bson.M{"$project": bson.M{
+			"_id":       0,
+			"chain_id":  1,
+			"height":    1,
+			"tx_hash":   "$hash",
+			"tx_id":     "$_id",
+			"msg_index": "$events.msg_index",
+			"msg_type":  msgAttributeValue("action"),
+			"module":    msgAttributeValue("module"),
+			"signer":    msgAttributeValue("sender"),
+			"time":      1,
+		}},
+		bson.M{"$match": msgMatch},
+	)
+}
+
+// msgAttributeValue returns the value of the first attribute of the message
+// event with the given key.
+func msgAttributeValue(key string) bson.M {
+	return bson.M{
+		"$let": bson.M{
+			"vars": bson.M{
+				"attrs": bson.M{
+					"$filter": bson.M{
+						"input": "$events.attributes",
+						"cond":  bson.M{"$eq": bson.A{"$$this.key", key}},
+					},
+				},
+			},
+			"in": bson.M{"$arrayElemAt": bson.A{"$$attrs.value", 0}},
+		},
+	}
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/utility"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// merkledropProofCollectionName is the collection of the merkledrop proofs,
+// they are stored by the model package.
+const merkledropProofCollectionName = "merkledrop_proofs"
+
+// CoinCollections are the collections storing modelv2.Coin documents.
+var CoinCollections = []string{
+	transactionCollectionName,
+	swapCollectionName,
+	poolCollectionName,
+	incentiveCollectionName,
+	liquidityEventCollectionName,
+	historicalLiquidityCollectionName,
+}
+
+// MigrateCoins rewrites the coins of the collection stored with a numeric
+// amount to the exact string amount, adding the amount_dec field. Documents
+// already migrated are left untouched, so the migration can be run again.
+// It returns the number of updated documents.
+func MigrateCoins(collectionName string, batchSize int) (int64, error) {
+	ctx := context.Background()
+	coll := db.GetCollection(collectionName, "default")
+
+	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetBatchSize(int32(batchSize)))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	var models []mongo.WriteModel
+
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+
+		res, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+
+		updated += res.ModifiedCount
+		models = models[:0]
+
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var doc bson.D
+		if err := cursor.Decode(&doc); err != nil {
+			return updated, err
+		}
+
+		migrated, changed, err := migrateCoinValue(doc)
+		if err != nil {
+			return updated, fmt.Errorf("%s: %w", collectionName, err)
+		}
+
+		if !changed {
+			continue
+		}
+
+		filter := bson.M{"_id": doc.Map()["_id"]}
+		models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(migrated))
+
+		if len(models) >= batchSize {
+			if err := flush(); err != nil {
+				return updated, err
+			}
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return updated, err
+	}
+
+	if err := flush(); err != nil {
+		return updated, err
+	}
+
+	return updated, nil
+}
+
+// migrateCoinValue walks value and converts every embedded coin.
+func migrateCoinValue(value interface{}) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case bson.D:
+		if coin, ok, err := migrateCoin(v); ok || err != nil {
+			return coin, ok, err
+		}
+
+		changed := false
+		for i, elem := range v {
+			migrated, elemChanged, err := migrateCoinValue(elem.Value)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if elemChanged {
+				v[i].Value = migrated
+				changed = true
+			}
+		}
+
+		return v, changed, nil
+
+	case bson.A:
+		changed := false
+		for i, elem := range v {
+			migrated, elemChanged, err := migrateCoinValue(elem)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if elemChanged {
+				v[i] = migrated
+				changed = true
+			}
+		}
+
+		return v, changed, nil
+	}
+
+	return value, false, nil
+}
+
+// migrateCoin converts doc if it is a coin with a numeric amount.
+func migrateCoin(doc bson.D) (bson.D, bool, error) {
+	fields := doc.Map()
+
+	denom, ok := fields["denom"].(string)
+	if !ok || len(fields) > 3 {
+		return nil, false, nil
+	}
+
+	var amount string
+
+	switch a := fields["amount"].(type) {
+	case float64:
+		amount = strconv.FormatFloat(a, 'f', 0, 64)
+	case int32:
+		amount = strconv.FormatInt(int64(a), 10)
+	case int64:
+		amount = strconv.FormatInt(a, 10)
+	case string:
+		if _, ok := fields["amount_dec"]; ok {
+			return nil, false, nil
+		}
+		amount = a
+	default:
+		return nil, false, nil
+	}
+
+	coin, err := modelv2.NewCoin(denom, amount)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return bson.D{
+		{Key: "amount", Value: coin.Amount},
+		{Key: "amount_dec", Value: coin.AmountDec},
+		{Key: "denom", Value: coin.Denom},
+	}, true, nil
+}
+
+// AddressField is a field storing an address and the field storing its key.
+type AddressField struct {
+	Collection string
+	Address    string
+	Key        string
+}
+
+// AddressFields are the address fields keyed by utility.AddressKey.
+var AddressFields = []AddressField{
+	{Collection: accountCollectionName, Address: "address", Key: "address_key"},
+	{Collection: swapCollectionName, Address: "account", Key: "account_key"},
+	{Collection: liquidityEventCollectionName, Address: "sender", Key: "sender_key"},
+	{Collection: merkledropProofCollectionName, Address: "address", Key: "address_key"},
+}
+
+// MigrateAddressKeys sets the key of the addresses stored before the keys
+// were written with the documents. The documents with a key are left
+// untouched, so the migration can be run again. It returns the number of
+// updated documents.
+func MigrateAddressKeys(field AddressField, batchSize int) (int64, error) {
+	ctx := context.Background()
+	coll := db.GetCollection(field.Collection, "default")
+
+	filter := bson.M{field.Key: bson.M{"$exists": false}}
+	opts := options.Find().
+		SetProjection(bson.M{field.Address: 1}).
+		SetBatchSize(int32(batchSize))
+
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var updated int64
+	var models []mongo.WriteModel
+
+	flush := func() error {
+		if len(models) == 0 {
+			return nil
+		}
+
+		res, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+
+		updated += res.ModifiedCount
+		models = models[:0]
+
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		doc := cursor.Current
+
+		address, _ := doc.Lookup(field.Address).StringValueOK()
+		// an address that is not bech32 gets an empty key
+		key, _ := utility.AddressKey(address)
+
+		update := bson.M{"$set": bson.M{field.Key: key}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": doc.Lookup("_id")}).SetUpdate(update))
+
+		if len(models) >= batchSize {
+			if err := flush(); err != nil {
+				return updated, err
+			}
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return updated, err
+	}
+
+	if err := flush(); err != nil {
+		return updated, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/angelorc/sinfonia-go/utility"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sortKey struct {
+	name  string
+	order int
+}
+
+// sortKeys returns the sort of a paginated list: the order key and _id, so
+// that the order is stable and an item is identified by its sort values.
+func sortKeys(pagination *types.PaginationReq, defaultKey string, defaultOrder int) []sortKey {
+	key, order := defaultKey, defaultOrder
+	if pagination.OrderBy != nil {
+		key, order = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
+	}
+
+	return []sortKey{{name: key, order: order}, {name: "_id", order: order}}
+}
+
+func sortDoc(keys []sortKey) bson.D {
+	sort := bson.D{}
+	for _, key := range keys {
+		sort = append(sort, bson.E{Key: key.name, Value: key.order})
+	}
+
+	return sort
+}
+
+// paginate returns the filter and the options of a Find paginated by
+// pagination. When a cursor is given the items after it are found with a
+// range on the sort keys, instead of skipping the previous pages.
+func paginate(filter interface{}, pagination *types.PaginationReq, keys []sortKey) (interface{}, *options.FindOptions, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	opts := options.Find().SetSort(sortDoc(keys))
+	if pagination.Limit != nil {
+		opts.SetLimit(*pagination.Limit)
+	}
+	if pagination.Skip != nil {
+		opts.SetSkip(*pagination.Skip)
+	}
+
+	if pagination.After == nil {
+		return filter, opts, nil
+	}
+
+	cursor, err := decodeCursor(*pagination.After, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seek, err := seekFilter(cursor.Values, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bson.M{"$and": bson.A{filter, seek}}, opts, nil
+}
+
+// Paginate returns the filter and the options of a Find paginated by
+// pagination and sorted by the order key and _id, for the models read
+// without a repository.
+func Paginate(filter interface{}, pagination *types.PaginationReq, defaultKey string, defaultOrder int) (interface{}, *options.FindOptions, error) {
+	return paginate(filter, pagination, sortKeys(pagination, defaultKey, defaultOrder))
+}
+
+// decodeCursor decodes a cursor and checks that it has been created for the
+// list sorted by keys.
+func decodeCursor(after string, keys []sortKey) (types.Cursor, error) {
+	cursor, err := types.DecodeCursor(after)
+	if err != nil {
+		return types.Cursor{}, err
+	}
+
+	if len(cursor.Keys) != len(keys) {
+		return types.Cursor{}, types.ErrInvalidCursor
+	}
+	for i, key := range keys {
+		if cursor.Keys[i] != key.name {
+			return types.Cursor{}, types.ErrInvalidCursor
+		}
+	}
+
+	return cursor, nil
+}
+
+// seekFilter matches the items sorted after values: the items with a
+// following first key, or the same first key and a following second key...
+// The values come from the client, only the scalar types of the sort keys are
+// accepted, so that a value is never read as a query document.
+func seekFilter(values []bson.RawValue, keys []sortKey) (bson.M, error) {
+	if len(values) != len(keys) {
+		return nil, types.ErrInvalidCursor
+	}
+	for _, value := range values {
+		if _, ok := seekTypes[value.Type]; !ok {
+			return nil, types.ErrInvalidCursor
+		}
+	}
+
+	or := bson.A{}
+	for i, key := range keys {
+		cond := bson.M{}
+		for j := 0; j < i; j++ {
+			cond[keys[j].name] = values[j]
+		}
+		cond[key.name] = bson.M{seekOperator(key.order): values[i]}
+
+		or = append(or, cond)
+	}
+
+	// the bound on the first key lets the planner scan only the index range
+	// after the cursor
+	first := "$gte"
+	if keys[0].order < 0 {
+		first = "$lte"
+	}
+
+	return bson.M{keys[0].name: bson.M{first: values[0]}, "$or": or}, nil
+}
+
+// seekTypes are the bson types of the values of a cursor.
+var seekTypes = map[bsontype.Type]struct{}{
+	bsontype.String:     {},
+	bsontype.Int32:      {},
+	bsontype.Int64:      {},
+	bsontype.Double:     {},
+	bsontype.Decimal128: {},
+	bsontype.DateTime:   {},
+	bsontype.Timestamp:  {},
+	bsontype.ObjectID:   {},
+	bsontype.Boolean:    {},
+	bsontype.Null:       {},
+}
+
+func seekOperator(order int) string {
+	if order < 0 {
+		return "$lt"
+	}
+
+	return "$gt"
+}
+
+// sortIndexes returns the indexes used by the paginated lists sorted by keys.
+func sortIndexes(keys ...string) []mongo.IndexModel {
+	indexes := make([]mongo.IndexModel, 0, len(keys))
+	for _, key := range keys {
+		indexes = append(indexes, mongo.IndexModel{
+			Keys:    bson.D{{Key: key, Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetUnique(false),
+		})
+	}
+
+	return indexes
+}
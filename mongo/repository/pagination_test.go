@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSeekFilter(t *testing.T) {
+	keys := []sortKey{{name: "height", order: -1}, {name: "_id", order: -1}}
+
+	rawValue := func(v interface{}) bson.RawValue {
+		doc, err := bson.Marshal(bson.M{"v": v})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return bson.Raw(doc).Lookup("v")
+	}
+
+	tests := []struct {
+		name   string
+		values []bson.RawValue
+		valid  bool
+	}{
+		{"scalar values", []bson.RawValue{rawValue(int64(4521)), rawValue(primitive.NewObjectID())}, true},
+		{"query document", []bson.RawValue{rawValue(bson.M{"$ne": 0}), rawValue(primitive.NewObjectID())}, false},
+		{"array", []bson.RawValue{rawValue(int64(4521)), rawValue(bson.A{1, 2})}, false},
+		{"missing value", []bson.RawValue{rawValue(int64(4521))}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seek, err := seekFilter(tt.values, keys)
+			if !tt.valid {
+				if err != types.ErrInvalidCursor {
+					t.Fatalf("expected ErrInvalidCursor, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := seek["height"].(bson.M)["$lte"]; !ok {
+				t.Fatalf("expected a $lte bound on the first key, got %v", seek)
+			}
+			if or := seek["$or"].(bson.A); len(or) != len(keys) {
+				t.Fatalf("expected %d conditions, got %d", len(keys), len(or))
+			}
+		})
+	}
+}
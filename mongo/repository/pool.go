@@ -6,7 +6,6 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
 	"github.com/angelorc/sinfonia-go/mongo/types"
-	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -33,6 +32,10 @@ type PoolRepository interface {
 	FindByPoolID(poolID uint64) *modelv2.Pool
 
 	Create(data *modelv2.PoolCreateReq) (*primitive.ObjectID, error)
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
+
+	FindAssetsAboveHeight(chainID string, height int64) ([]*modelv2.Pool, error)
+	UpdateAssets(id primitive.ObjectID, assets []modelv2.PoolAsset, swapFee, exitFee float64, height int64) error
 }
 
 func NewPoolRepository() PoolRepository {
@@ -61,27 +64,17 @@ func (e *poolRepository) FindByPoolID(poolID uint64) *modelv2.Pool {
 func (e *poolRepository) Find(filter *modelv2.PoolFilter, pagination *types.PaginationReq) ([]*modelv2.Pool, error) {
 	var pools []*modelv2.Pool
 
-	orderByKey := "height"
-	orderByValue := -1
-
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
-	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
-	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
-	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
-
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := e.collection.Find(e.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return pools, err
+	}
+
+	cursor, err := e.collection.Find(e.context, query, options)
 	if err != nil {
 		return pools, err
 	}
@@ -117,6 +110,49 @@ func (e *poolRepository) Create(data *modelv2.PoolCreateReq) (*primitive.ObjectI
 	return &insertedID, nil
 }
 
+func (e *poolRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+// FindAssetsAboveHeight returns the pools created up to height whose assets
+// were queried above it, e.g. the pools queried on the blocks rolled back by a
+// fork.
+func (e *poolRepository) FindAssetsAboveHeight(chainID string, height int64) ([]*modelv2.Pool, error) {
+	var pools []*modelv2.Pool
+
+	filter := bson.M{"chain_id": chainID, "height": bson.M{"$lte": height}, "assets_height": bson.M{"$gt": height}}
+
+	cursor, err := e.collection.Find(e.context, filter)
+	if err != nil {
+		return pools, err
+	}
+	err = cursor.All(e.context, &pools)
+	if err != nil {
+		return pools, err
+	}
+
+	return pools, nil
+}
+
+// UpdateAssets replaces the assets, the weights and the fees of a pool with
+// the ones queried at height.
+func (e *poolRepository) UpdateAssets(id primitive.ObjectID, assets []modelv2.PoolAsset, swapFee, exitFee float64, height int64) error {
+	update := bson.M{"$set": bson.M{
+		"pool_assets":   assets,
+		"swap_fee":      swapFee,
+		"exit_fee":      exitFee,
+		"assets_height": height,
+	}}
+
+	_, err := e.collection.UpdateByID(e.context, id, update)
+	return err
+}
+
 func (e *poolRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -134,5 +170,7 @@ func (e *poolRepository) EnsureIndexes() (string, error) {
 		Options: options.Index().SetUnique(true),
 	}
 
+	e.collection.Indexes().CreateMany(e.context, sortIndexes("height", "time", "pool_id"))
+
 	return e.collection.Indexes().CreateOne(e.context, index)
 }
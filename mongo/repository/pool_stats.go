@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	poolStatsCollectionName = "pool_stats"
+	poolStatsDbRefName      = "default"
+)
+
+type poolStatsRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+type PoolStatsRepository interface {
+	Find(filter *modelv2.PoolStatsFilter) ([]*modelv2.PoolStats, error)
+	FindByPoolID(chainID string, poolID uint64) *modelv2.PoolStats
+	FindByPoolIDs(chainID string, poolIDs []uint64) ([]*modelv2.PoolStats, error)
+	EnsureIndexes() (string, error)
+
+	Upsert(stats *modelv2.PoolStats) error
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
+}
+
+func NewPoolStatsRepository() PoolStatsRepository {
+	coll := db.GetCollection(poolStatsCollectionName, poolStatsDbRefName)
+	ctx := context.Background()
+
+	return &poolStatsRepository{context: ctx, collection: coll}
+}
+
+// Find returns the stats of the pools matching filter, sorted by 24h volume.
+func (e *poolStatsRepository) Find(filter *modelv2.PoolStatsFilter) ([]*modelv2.PoolStats, error) {
+	var results []*modelv2.PoolStats
+
+	var queryFilter interface{} = bson.M{}
+	if filter != nil {
+		queryFilter = filter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "volume_24h", Value: -1}, {Key: "pool_id", Value: 1}})
+
+	cursor, err := e.collection.Find(e.context, queryFilter, opts)
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// FindByPoolID returns the stats of the pool, empty stats are returned when
+// they have not been computed.
+func (e *poolStatsRepository) FindByPoolID(chainID string, poolID uint64) *modelv2.PoolStats {
+	var stats modelv2.PoolStats
+	e.collection.FindOne(e.context, bson.M{"chain_id": chainID, "pool_id": poolID}).Decode(&stats)
+
+	return &stats
+}
+
+// FindByPoolIDs returns the stats computed of the pools of the chain.
+func (e *poolStatsRepository) FindByPoolIDs(chainID string, poolIDs []uint64) ([]*modelv2.PoolStats, error) {
+	var results []*modelv2.PoolStats
+
+	cursor, err := e.collection.Find(e.context, bson.M{"chain_id": chainID, "pool_id": bson.M{"$in": poolIDs}})
+	if err != nil {
+		return results, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// Upsert replaces the stats of the pool.
+func (e *poolStatsRepository) Upsert(stats *modelv2.PoolStats) error {
+	filter := bson.M{"chain_id": stats.ChainID, "pool_id": stats.PoolID}
+
+	// the _id of the stored stats is kept
+	replacement := *stats
+	replacement.ID = primitive.NilObjectID
+
+	_, err := e.collection.ReplaceOne(e.context, filter, &replacement, options.Replace().SetUpsert(true))
+	return err
+}
+
+// DeleteAboveHeight removes the stats of chainID computed above height, they
+// are computed again by the next sync.
+func (e *poolStatsRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+func (e *poolStatsRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "volume_24h", Value: -1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "chain_id", Value: 1},
+			{Key: "pool_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	return e.collection.Indexes().CreateOne(e.context, index)
+}
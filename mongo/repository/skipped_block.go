@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	skippedBlockCollectionName = "skipped_blocks"
+	skippedBlockDbRefName      = "default"
+)
+
+type skippedBlockRepository struct {
+	context    context.Context
+	collection *mongo.Collection
+}
+
+// SkippedBlockRepository stores the heights the indexer could not decode.
+type SkippedBlockRepository interface {
+	Find(chainID string) ([]*modelv2.SkippedBlock, error)
+	EnsureIndexes() (string, error)
+
+	Save(chainID string, height int64, reason string) error
+	Delete(chainID string, height int64) error
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
+}
+
+func NewSkippedBlockRepository() SkippedBlockRepository {
+	coll := db.GetCollection(skippedBlockCollectionName, skippedBlockDbRefName)
+	ctx := context.Background()
+
+	repo := &skippedBlockRepository{context: ctx, collection: coll}
+	repo.EnsureIndexes()
+
+	return repo
+}
+
+// Find returns the skipped blocks of the chain, sorted by height.
+func (e *skippedBlockRepository) Find(chainID string) ([]*modelv2.SkippedBlock, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "height", Value: 1}})
+
+	cursor, err := e.collection.Find(e.context, bson.M{"chain_id": chainID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*modelv2.SkippedBlock, 0)
+	if err := cursor.All(e.context, &blocks); err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// Save records the skipped height, the error of a height already recorded is
+// replaced.
+func (e *skippedBlockRepository) Save(chainID string, height int64, reason string) error {
+	filter := bson.M{"chain_id": chainID, "height": height}
+	update := bson.M{"$set": bson.M{"error": reason, "updated_at": time.Now()}}
+
+	_, err := e.collection.UpdateOne(e.context, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// Delete removes the height once it has been indexed.
+func (e *skippedBlockRepository) Delete(chainID string, height int64) error {
+	_, err := e.collection.DeleteOne(e.context, bson.M{"chain_id": chainID, "height": height})
+	return err
+}
+
+// DeleteAboveHeight removes the skipped heights of chainID above height, they
+// are indexed again after a rollback.
+func (e *skippedBlockRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
+func (e *skippedBlockRepository) EnsureIndexes() (string, error) {
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "chain_id", Value: 1},
+			{Key: "height", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+
+	return e.collection.Indexes().CreateOne(e.context, index)
+}
@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
 )
 
 const (
@@ -31,9 +32,17 @@ type SwapRepository interface {
 
 	FindByID(id primitive.ObjectID) *modelv2.Swap
 	FindByHeight(height int64) *modelv2.Swap
+	FindByTime(chainID string, poolID *int64, from, to time.Time) ([]*modelv2.Swap, error)
+	FindByHeightRange(chainID string, fromHeight, toHeight int64) ([]*modelv2.Swap, error)
+	FindLastPrice(poolID int64, before time.Time) *modelv2.Swap
+	Volumes(chainID string, from, to time.Time) ([]*modelv2.SwapVolume, error)
+	SumByAccounts(accounts []string) (*modelv2.AccountSwaps, error)
+	FindToReprice(chainID string, maxAge time.Duration, after primitive.ObjectID, limit int64) ([]*modelv2.Swap, error)
+	UpdateUsdValue(id primitive.ObjectID, usdValue float64, priceAge *int64) error
 
 	Create(data *modelv2.SwapCreateReq) (*primitive.ObjectID, error)
 	InsertMany(records []interface{}) (*mongo.InsertManyResult, error)
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewSwapRepository() SwapRepository {
@@ -69,30 +78,214 @@ func (e *swapRepository) FindByHeight(height int64) *modelv2.Swap {
 	return e.FindOne(&modelv2.SwapFilter{Height: &height})
 }
 
-func (e *swapRepository) Find(filter *modelv2.SwapFilter, pagination *types.PaginationReq) ([]*modelv2.Swap, error) {
+// FindByTime returns the swaps of the chain, or of a single pool, executed
+// from time from (included) to time to (excluded), sorted by time.
+func (e *swapRepository) FindByTime(chainID string, poolID *int64, from, to time.Time) ([]*modelv2.Swap, error) {
+	var swaps []*modelv2.Swap
+
+	filter := bson.M{
+		"chain_id": chainID,
+		"time":     bson.M{"$gte": from, "$lt": to},
+	}
+	if poolID != nil {
+		filter["pool_id"] = *poolID
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}, {Key: "height", Value: 1}})
+
+	cursor, err := e.collection.Find(e.context, filter, opts)
+	if err != nil {
+		return swaps, err
+	}
+	err = cursor.All(e.context, &swaps)
+	if err != nil {
+		return swaps, err
+	}
+
+	return swaps, nil
+}
+
+// FindByHeightRange returns the swaps of the chain from height fromHeight to
+// height toHeight (both included), sorted by height.
+func (e *swapRepository) FindByHeightRange(chainID string, fromHeight, toHeight int64) ([]*modelv2.Swap, error) {
+	var swaps []*modelv2.Swap
+
+	filter := bson.M{
+		"chain_id": chainID,
+		"height":   bson.M{"$gte": fromHeight, "$lte": toHeight},
+	}
+
+	cursor, err := e.collection.Find(e.context, filter, options.Find().SetSort(bson.D{{Key: "height", Value: 1}}))
+	if err != nil {
+		return swaps, err
+	}
+	err = cursor.All(e.context, &swaps)
+	if err != nil {
+		return swaps, err
+	}
+
+	return swaps, nil
+}
+
+// FindLastPrice returns the last swap of the pool with a price executed up to
+// time before, an empty swap is returned when there is none.
+func (e *swapRepository) FindLastPrice(poolID int64, before time.Time) *modelv2.Swap {
+	var swap modelv2.Swap
+
+	filter := bson.M{
+		"pool_id": poolID,
+		"price":   bson.M{"$gt": 0},
+		"time":    bson.M{"$lte": before},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "time", Value: -1}, {Key: "height", Value: -1}})
+	e.collection.FindOne(e.context, filter, opts).Decode(&swap)
+
+	return &swap
+}
+
+// Volumes returns the volume of the swaps executed from time from (included)
+// to time to (excluded), by pool and denom of the token in.
+func (e *swapRepository) Volumes(chainID string, from, to time.Time) ([]*modelv2.SwapVolume, error) {
+	var volumes []*modelv2.SwapVolume
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"chain_id": chainID,
+			"time":     bson.M{"$gte": from, "$lt": to},
+		}},
+		{"$group": bson.M{
+			"_id":       bson.M{"pool_id": "$pool_id", "denom": "$token_in.denom"},
+			"usd_value": bson.M{"$sum": "$usd_value"},
+			"fee":       bson.M{"$sum": "$fee"},
+			"trades":    bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":       0,
+			"pool_id":   "$_id.pool_id",
+			"denom":     "$_id.denom",
+			"usd_value": 1,
+			"fee":       1,
+			"trades":    1,
+		}},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return volumes, err
+	}
+	err = cursor.All(e.context, &volumes)
+	if err != nil {
+		return volumes, err
+	}
+
+	return volumes, nil
+}
+
+// SumByAccounts sums the swaps of accounts. The fee is paid in the token in,
+// so its USD value is the share of the value of the swap.
+func (e *swapRepository) SumByAccounts(accounts []string) (*modelv2.AccountSwaps, error) {
+	var results []*modelv2.AccountSwaps
+
+	feeValue := bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$token_in.amount_dec", 0}},
+		bson.M{"$divide": bson.A{
+			bson.M{"$multiply": bson.A{"$usd_value", "$fee"}},
+			bson.M{"$toDouble": "$token_in.amount_dec"},
+		}},
+		0,
+	}}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"account": bson.M{"$in": accounts}}},
+		{"$group": bson.M{
+			"_id":        nil,
+			"count":      bson.M{"$sum": 1},
+			"volume":     bson.M{"$sum": "$usd_value"},
+			"fees_paid":  bson.M{"$sum": feeValue},
+			"first_time": bson.M{"$min": "$time"},
+			"last_time":  bson.M{"$max": "$time"},
+		}},
+	}
+
+	cursor, err := e.collection.Aggregate(e.context, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(e.context, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return &modelv2.AccountSwaps{}, nil
+	}
+
+	return results[0], nil
+}
+
+// FindToReprice returns the swaps of the chain with the id greater than after
+// valued without a price, with a price older than maxAge, or synced before
+// the age of the price was stored, sorted by id. A zero maxAge does not
+// select the swaps by age.
+func (e *swapRepository) FindToReprice(chainID string, maxAge time.Duration, after primitive.ObjectID, limit int64) ([]*modelv2.Swap, error) {
 	var swaps []*modelv2.Swap
 
-	orderByKey := "height"
-	orderByValue := -1
+	conditions := bson.A{
+		bson.M{"usd_value": 0},
+		bson.M{"usd_price_age": bson.M{"$exists": false}},
+	}
+	if maxAge > 0 {
+		conditions = append(conditions, bson.M{"usd_price_age": bson.M{"$gt": int64(maxAge.Seconds())}})
+	}
+
+	filter := bson.M{
+		"chain_id": chainID,
+		"_id":      bson.M{"$gt": after},
+		"$or":      conditions,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
 
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
+	cursor, err := e.collection.Find(e.context, filter, opts)
+	if err != nil {
+		return swaps, err
 	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
+	err = cursor.All(e.context, &swaps)
+	if err != nil {
+		return swaps, err
 	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
+
+	return swaps, nil
+}
+
+// UpdateUsdValue sets the USD value of a swap and the age of its price, the
+// age is removed when priceAge is nil.
+func (e *swapRepository) UpdateUsdValue(id primitive.ObjectID, usdValue float64, priceAge *int64) error {
+	update := bson.M{"$set": bson.M{"usd_value": usdValue, "usd_price_age": priceAge}}
+	if priceAge == nil {
+		update = bson.M{
+			"$set":   bson.M{"usd_value": usdValue},
+			"$unset": bson.M{"usd_price_age": ""},
+		}
 	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
+
+	_, err := e.collection.UpdateByID(e.context, id, update)
+	return err
+}
+
+func (e *swapRepository) Find(filter *modelv2.SwapFilter, pagination *types.PaginationReq) ([]*modelv2.Swap, error) {
+	var swaps []*modelv2.Swap
 
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := e.collection.Find(e.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return swaps, err
+	}
+
+	cursor, err := e.collection.Find(e.context, query, options)
 	if err != nil {
 		return swaps, err
 	}
@@ -110,6 +303,7 @@ func (e *swapRepository) Count(filter *modelv2.SwapFilter) (int64, error) {
 
 func (e *swapRepository) Create(data *modelv2.SwapCreateReq) (*primitive.ObjectID, error) {
 	data.ID = primitive.NewObjectID()
+	data.AccountKey, _ = utility.AddressKey(data.Account)
 
 	if err := data.Validate(); err != nil {
 		return &primitive.ObjectID{}, err
@@ -132,6 +326,15 @@ func (e *swapRepository) InsertMany(records []interface{}) (*mongo.InsertManyRes
 	return e.collection.InsertMany(e.context, records)
 }
 
+func (e *swapRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := e.collection.DeleteMany(e.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 func (e *swapRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -142,6 +345,36 @@ func (e *swapRepository) EnsureIndexes() (string, error) {
 
 	e.collection.Indexes().CreateOne(e.context, index)
 
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "pool_id", Value: 1},
+			{Key: "time", Value: 1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "account", Value: 1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	index = mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "account_key", Value: 1},
+		},
+		Options: options.Index().SetUnique(false),
+	}
+
+	e.collection.Indexes().CreateOne(e.context, index)
+
+	e.collection.Indexes().CreateMany(e.context, sortIndexes("height", "time"))
+
 	index = mongo.IndexModel{
 		Keys: bson.D{
 			{"tx_hash", 1},
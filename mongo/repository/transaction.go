@@ -6,7 +6,6 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
 	"github.com/angelorc/sinfonia-go/mongo/types"
-	"github.com/angelorc/sinfonia-go/utility"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -31,9 +30,10 @@ type TransactionRepository interface {
 
 	FindByID(id primitive.ObjectID) *modelv2.Transaction
 	FindByHash(hash string) *modelv2.Transaction
-	FindEventsByTypes(fields []bson.M, fromBlock, toBlock int64) ([]*modelv2.TransactionEvents, error)
+	FindEventsByTypes(chainID string, fields []bson.M, fromBlock, toBlock int64) ([]*modelv2.TransactionEvents, error)
 
 	Create(data *modelv2.TransactionCreateReq) (*modelv2.Transaction, error)
+	DeleteAboveHeight(chainID string, height int64) (int64, error)
 }
 
 func NewTransactionRepository() TransactionRepository {
@@ -62,27 +62,17 @@ func (b *transactionRepository) FindByHash(hash string) *modelv2.Transaction {
 func (b *transactionRepository) Find(filter *modelv2.TransactionFilter, pagination *types.PaginationReq) ([]*modelv2.Transaction, error) {
 	var transactions []*modelv2.Transaction
 
-	orderByKey := "height"
-	orderByValue := -1
-
-	options := options.Find()
-	if pagination.Limit != nil {
-		options.SetLimit(*pagination.Limit)
-	}
-	if pagination.Skip != nil {
-		options.SetSkip(*pagination.Skip)
-	}
-	if pagination.OrderBy != nil {
-		orderByKey, orderByValue = utility.GetOrderByKeyAndValue(*pagination.OrderBy)
-	}
-	options.SetSort(map[string]int{orderByKey: orderByValue})
-
 	var queryFilter interface{}
 	if filter != nil {
 		queryFilter = filter
 	}
 
-	cursor, err := b.collection.Find(b.context, &queryFilter, options)
+	query, options, err := paginate(queryFilter, pagination, sortKeys(pagination, "height", -1))
+	if err != nil {
+		return transactions, err
+	}
+
+	cursor, err := b.collection.Find(b.context, query, options)
 	if err != nil {
 		return transactions, err
 	}
@@ -123,6 +113,15 @@ func (b *transactionRepository) Create(data *modelv2.TransactionCreateReq) (*mod
 	return b.FindByID(insertedID), nil
 }
 
+func (b *transactionRepository) DeleteAboveHeight(chainID string, height int64) (int64, error) {
+	res, err := b.collection.DeleteMany(b.context, bson.M{"chain_id": chainID, "height": bson.M{"$gt": height}})
+	if err != nil {
+		return 0, err
+	}
+
+	return res.DeletedCount, nil
+}
+
 func (b *transactionRepository) EnsureIndexes() (string, error) {
 	index := mongo.IndexModel{
 		Keys: bson.D{
@@ -149,10 +148,12 @@ func (b *transactionRepository) EnsureIndexes() (string, error) {
 		Options: options.Index().SetUnique(true),
 	}
 
+	b.collection.Indexes().CreateMany(b.context, sortIndexes("height", "time"))
+
 	return b.collection.Indexes().CreateOne(b.context, index)
 }
 
-func (e *transactionRepository) FindEventsByTypes(fields []bson.M, fromBlock, toBlock int64) ([]*modelv2.TransactionEvents, error) {
+func (e *transactionRepository) FindEventsByTypes(chainID string, fields []bson.M, fromBlock, toBlock int64) ([]*modelv2.TransactionEvents, error) {
 	var txEvents []*modelv2.TransactionEvents
 
 	pipeline := []bson.M{
@@ -163,7 +164,7 @@ func (e *transactionRepository) FindEventsByTypes(fields []bson.M, fromBlock, to
 		},
 		{
 			"$match": bson.M{
-				"chain_id": "osmosis-1",
+				"chain_id": chainID,
 				"height": bson.M{
 					"$gte": fromBlock,
 					"$lte": toBlock,
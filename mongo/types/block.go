@@ -7,8 +7,9 @@ import (
 )
 
 type BlockFilter struct {
-	Id     *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	Height *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	Id      *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height  *int64              `json:"height,omitempty" bson:"height,omitempty"`
 }
 
 func (bf *BlockFilter) Validate() error {
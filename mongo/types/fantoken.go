@@ -10,8 +10,12 @@ type Asset struct {
 }
 
 type FantokenFilter struct {
-	Id    *primitive.ObjectID `json:"id,omitempty" bson:"id,omitempty"`
-	Denom *string             `json:"denom,omitempty" bson:"denom,omitempty"`
+	Id      *primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChainID *string             `json:"chain_id,omitempty" bson:"chain_id,omitempty"`
+	Height  *int64              `json:"height,omitempty" bson:"height,omitempty"`
+	Denom   *string             `json:"denom,omitempty" bson:"denom,omitempty"`
+	Alias   *string             `json:"alias,omitempty" bson:"alias,omitempty"`
+	Owner   *string             `json:"owner,omitempty" bson:"owner,omitempty"`
 }
 
 func (ff *FantokenFilter) Validate() error {
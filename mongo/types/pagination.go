@@ -1,7 +1,77 @@
 package types
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
 type PaginationReq struct {
 	Limit   *int64  `json:"limit,omitempty"`
 	Skip    *int64  `json:"skip,omitempty"`
 	OrderBy *string `json:"order_by,omitempty"`
+	// After is the encoded Cursor of the last item of the previous page, the
+	// items sorted after it are returned.
+	After *string `json:"after,omitempty"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of an item in a sorted list, it holds the names and
+// the values of the sort keys of the item, e.g. its height and its _id.
+type Cursor struct {
+	Keys   []string        `bson:"k"`
+	Values []bson.RawValue `bson:"v"`
+}
+
+// NewCursor returns the cursor of item in a list sorted by keys, the keys are
+// the bson field names of item.
+func NewCursor(item interface{}, keys ...string) (Cursor, error) {
+	doc, err := bson.Marshal(item)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	cursor := Cursor{Keys: keys, Values: make([]bson.RawValue, len(keys))}
+	for i, key := range keys {
+		value, err := bson.Raw(doc).LookupErr(key)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("missing cursor key %s: %w", key, err)
+		}
+
+		cursor.Values[i] = value
+	}
+
+	return cursor, nil
+}
+
+// DecodeCursor decodes a cursor encoded with Cursor.Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := bson.Unmarshal(bz, &cursor); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if len(cursor.Keys) == 0 || len(cursor.Keys) != len(cursor.Values) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// Encode returns the opaque string representation of the cursor.
+func (c Cursor) Encode() string {
+	bz, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bz)
 }
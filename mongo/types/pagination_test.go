@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursor(t *testing.T) {
+	item := struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Height int64              `bson:"height"`
+		Time   time.Time          `bson:"time"`
+	}{
+		ID:     primitive.NewObjectID(),
+		Height: 4521,
+		Time:   time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	cursor, err := NewCursor(item, "height", "_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.Keys) != 2 || decoded.Keys[0] != "height" || decoded.Keys[1] != "_id" {
+		t.Fatalf("unexpected keys %v", decoded.Keys)
+	}
+	if height := decoded.Values[0].Int64(); height != item.Height {
+		t.Fatalf("expected height %d, got %d", item.Height, height)
+	}
+	if id := decoded.Values[1].ObjectID(); id != item.ID {
+		t.Fatalf("expected _id %s, got %s", item.ID.Hex(), id.Hex())
+	}
+
+	if _, err := NewCursor(item, "pool_id"); err == nil {
+		t.Fatal("expected an error for a missing key")
+	}
+
+	for _, invalid := range []string{"", "not a cursor", "e30"} {
+		if _, err := DecodeCursor(invalid); err != ErrInvalidCursor {
+			t.Fatalf("expected ErrInvalidCursor for %q, got %v", invalid, err)
+		}
+	}
+}
@@ -24,8 +24,10 @@ import (
 	"google.golang.org/grpc"
 
 	"github.com/angelorc/sinfonia-go/indexer/types"
+	"github.com/angelorc/sinfonia-go/utility"
 
 	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	ibctypes "github.com/cosmos/ibc-go/v3/modules/apps/transfer/types"
 )
 
@@ -74,12 +76,16 @@ func (c *Client) ChainID() string {
 	return c.config.ChainID
 }
 
-func (c *Client) LatestBlockHeight(ctx context.Context) int64 {
+func (c *Client) LatestBlockHeight(ctx context.Context) (int64, error) {
 	status, err := c.rpc.Status(ctx)
 	if err != nil {
-		return 0
+		return 0, err
 	}
-	return status.SyncInfo.LatestBlockHeight
+	return status.SyncInfo.LatestBlockHeight, nil
+}
+
+func (c *Client) SubscribeNewBlocks(ctx context.Context) (<-chan int64, error) {
+	return tmcli.SubscribeNewBlocks(ctx, c.rpc, fmt.Sprintf("sinfonia-%s", c.config.ChainID))
 }
 
 func (c *Client) QueryBlock(ctx context.Context, height *int64) (*coretypes.ResultBlock, error) {
@@ -100,7 +106,7 @@ func (c *Client) QueryTx(ctx context.Context, hash []byte) (*tx.Tx, *sdk.TxRespo
 		var stdMsg sdk.Msg
 		err = c.Codec.Marshaler.UnpackAny(msg, &stdMsg)
 		if err != nil {
-			return nil, nil, fmt.Errorf("error while unpacking message: %s", err)
+			return nil, nil, fmt.Errorf("%w: error while unpacking message: %s", types.ErrDecodeTx, err)
 		}
 	}
 
@@ -142,6 +148,10 @@ func (c *Client) QueryIBCDenomTrace(hash string) (*ibctypes.QueryDenomTraceRespo
 	return ibctypes.NewQueryClient(c.grpc).DenomTrace(context.Background(), &ibctypes.QueryDenomTraceRequest{Hash: hash})
 }
 
+func (c *Client) QueryDenomMetadata(denom string) (*banktypes.QueryDenomMetadataResponse, error) {
+	return banktypes.NewQueryClient(c.grpc).DenomMetadata(context.Background(), &banktypes.QueryDenomMetadataRequest{Denom: denom})
+}
+
 /*func (c *Client) ParseTxFee(fees sdk.Coins) (string, string) {
 	var feeAmount, feeDenom string
 
@@ -157,7 +167,7 @@ func (c *Client) QueryIBCDenomTrace(hash string) (*ibctypes.QueryDenomTraceRespo
 }*/
 
 func (c *Client) EncodeBech32AccAddr(addr sdk.AccAddress) (string, error) {
-	return sdk.Bech32ifyAddressBytes(c.config.AccountPrefix, addr)
+	return utility.EncodeAddressBytes(c.config.AccountPrefix, addr)
 }
 func (c *Client) MustEncodeAccAddr(addr sdk.AccAddress) string {
 	enc, err := c.EncodeBech32AccAddr(addr)
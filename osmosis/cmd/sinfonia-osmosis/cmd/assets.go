@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/osmosis-labs/osmosis/v9/x/gamm/pool-models/balancer"
+)
+
+// loadAssetRegistry stores the assets of the config in the assets collection
+// and returns the registry with all the assets known for the chain.
+func loadAssetRegistry(chainCfg *config.ChainConfig) (*modelv2.AssetRegistry, error) {
+	assetRepo := repository.NewAssetRepository()
+
+	for _, asset := range chainCfg.Assets {
+		err := assetRepo.Upsert(&modelv2.Asset{
+			ChainID:       chainCfg.ChainID,
+			Denom:         asset.Denom,
+			Symbol:        asset.Symbol,
+			Decimals:      asset.Decimals,
+			CoingeckoID:   asset.CoingeckoID,
+			QuotePriority: asset.QuotePriority,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return assetRepo.Registry(chainCfg.ChainID)
+}
+
+func poolAssetsDenoms(poolAssets []balancer.PoolAsset) []string {
+	denoms := make([]string, len(poolAssets))
+
+	for i, pAsset := range poolAssets {
+		denoms[i] = pAsset.Token.Denom
+	}
+
+	return denoms
+}
@@ -1,15 +1,52 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/spf13/cobra"
+)
 
 const (
-	flagModules     = "modules"
-	flagConcurrent  = "concurrent"
-	flagStartHeight = "start-height"
-	flagEndHeight   = "end-height"
-	flagConfig      = "config"
+	flagModules       = "modules"
+	flagConcurrent    = "concurrent"
+	flagStartHeight   = "start-height"
+	flagEndHeight     = "end-height"
+	flagConfig        = "config"
+	flagFollow        = "follow"
+	flagPollInterval  = "poll-interval"
+	flagChainID       = "chain-id"
+	flagGenesisHeight = "genesis-height"
+	flagVerify        = "verify"
 )
 
 func addConfigFlag(cmd *cobra.Command) {
 	cmd.Flags().String(flagConfig, "./config.yml", "path to config file")
 }
+
+func addChainFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagChainID, "", "override the osmosis chain-id of the config file")
+	cmd.Flags().Int64(flagGenesisHeight, -1, "override the genesis height of every sync module")
+}
+
+// applyChainFlags overrides the chain config with the flags passed on the command line
+func applyChainFlags(cmd *cobra.Command, cfg *config.ChainConfig) error {
+	chainID, err := cmd.Flags().GetString(flagChainID)
+	if err != nil {
+		return err
+	}
+
+	if chainID != "" {
+		cfg.ChainID = chainID
+	}
+
+	genesisHeight, err := cmd.Flags().GetInt64(flagGenesisHeight)
+	if err != nil {
+		return err
+	}
+
+	if genesisHeight >= 0 {
+		cfg.GenesisHeight = genesisHeight
+		cfg.GenesisHeights = nil
+	}
+
+	return cfg.Validate()
+}
@@ -10,10 +10,17 @@ import (
 	"github.com/angelorc/sinfonia-go/osmosis/chain"
 	"github.com/spf13/cobra"
 	"log"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
+// derivedInterval is how often the derived modules are synced when following
+// the chain, the blocks indexed meanwhile are synced at once.
+const derivedInterval = time.Minute
+
 func IndexerCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "indexer",
@@ -42,6 +49,10 @@ func GetIndexerParserCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			/**
 			 * Connect to db
 			 */
@@ -79,7 +90,10 @@ func GetIndexerParserCmd() *cobra.Command {
 			}
 
 			if endHeight <= startHeight {
-				endHeight = client.LatestBlockHeight(context.Background())
+				endHeight, err = client.LatestBlockHeight(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to get the latest block height: %w", err)
+				}
 				syncAll = true
 			}
 
@@ -97,20 +111,72 @@ func GetIndexerParserCmd() *cobra.Command {
 				return fmt.Errorf("indicate modules to parse")
 			}
 
-			indexer.
-				NewIndexer(client, parseModules(modulesStr), concurrent).
-				Parse(startHeight, endHeight)
+			idx := indexer.NewIndexer(client, parseModules(modulesStr), concurrent)
 
-			if syncAll {
-				if err := syncPools(client); err != nil {
+			follow, err := cmd.Flags().GetBool(flagFollow)
+			if err != nil {
+				return err
+			}
+
+			// the blocks skipped by the previous runs are below the start height
+			if err := idx.RetrySkipped(); err != nil {
+				return err
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			if follow {
+				pollInterval, err := cmd.Flags().GetDuration(flagPollInterval)
+				if err != nil {
 					return err
 				}
 
-				/*if err := syncLiquidityEvents(); err != nil {
+				ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+				defer stop()
+
+				var derivedTime, statsTime time.Time
+
+				return idx.Follow(ctx, startHeight, pollInterval, func(_, _ int64) error {
+					if time.Since(derivedTime) < derivedInterval {
+						return nil
+					}
+					derivedTime = time.Now()
+
+					if err := syncDerived(client, &cfg.Osmosis, valuer); err != nil {
+						return err
+					}
+
+					if time.Since(statsTime) < poolStatsInterval {
+						return nil
+					}
+					statsTime = time.Now()
+
+					return syncPoolStats(client, &cfg.Osmosis, valuer)
+				})
+			}
+
+			if err := idx.Parse(startHeight, endHeight); err != nil {
+				return err
+			}
+
+			if skipped := idx.SkippedBlocks(); len(skipped) > 0 {
+				log.Printf("skipped %d blocks that could not be decoded: %v", len(skipped), skipped)
+			}
+
+			if syncAll {
+				if err := syncDerived(client, &cfg.Osmosis, valuer); err != nil {
 					return err
-				}*/
+				}
 
-				if err := syncSwaps(); err != nil {
+				if err := syncPoolStats(client, &cfg.Osmosis, valuer); err != nil {
 					return err
 				}
 			}
@@ -124,12 +190,38 @@ func GetIndexerParserCmd() *cobra.Command {
 
 	cmd.Flags().String(flagModules, "*", "modules to parse eg: * for all or \"blocks,transactions,messages,block-results\" ")
 	cmd.Flags().Int(flagConcurrent, 2, "how many concurrent indexer (do not abuse!)")
+	cmd.Flags().Bool(flagFollow, false, "keep indexing new blocks as they are produced, end-height is ignored")
+	cmd.Flags().Duration(flagPollInterval, 6*time.Second, "how often to poll the chain head when following, used as fallback for the websocket")
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
 
+// syncDerived runs the syncs deriving the osmosis modules from the indexed
+// blocks, each module after the ones it is built from. The candles are synced
+// with the swaps.
+func syncDerived(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
+	if err := syncPools(client, chainCfg, valuer.registry); err != nil {
+		return err
+	}
+
+	if err := syncLiquidityEvents(client, chainCfg, valuer); err != nil {
+		return err
+	}
+
+	if err := syncSwaps(client, chainCfg, valuer); err != nil {
+		return err
+	}
+
+	if err := syncIncentives(client, chainCfg, valuer); err != nil {
+		return err
+	}
+
+	return syncLiquidity(client, chainCfg, valuer, false)
+}
+
 func parseModules(flag string) *indexer.IndexModules {
 	modulesStr := strings.Split(flag, ",")
 	modules := &indexer.IndexModules{}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// repriceBatch is the number of swaps loaded at once
+const repriceBatch = 1000
+
+func GetRepriceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reprice",
+		Short:   "recompute the usd value of the swaps valued without a price or with a price older than the prices max-age",
+		Example: "sinfonia-osmosis reprice",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			return repriceSwaps(client, &cfg.Osmosis, valuer)
+		},
+	}
+
+	addConfigFlag(cmd)
+	addChainFlags(cmd)
+
+	return cmd
+}
+
+// checkpointLeaderboards is the checkpoint of the leaderboards synced by
+// sinfonia, they are built from the swaps values.
+const checkpointLeaderboards = "leaderboards"
+
+// repriceSwaps values again the swaps without a fresh price. The swaps still
+// without a fresh price keep their value. The candles and the pool stats are
+// rebuilt from the first repriced swap, the leaderboards checkpoint is moved
+// back before it so that the next leaderboards sync rebuilds the days. The
+// account totals are summed from the swaps when requested, they are never
+// stale.
+func repriceSwaps(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
+	maxAge, err := chainCfg.Prices.MaxPriceAge()
+	if err != nil {
+		return err
+	}
+
+	swapRepo := repository.NewSwapRepository()
+
+	repriced, unpriced := 0, 0
+	after := primitive.NilObjectID
+	// the lowest height of the repriced swaps
+	var fromHeight int64
+
+	for {
+		swaps, err := swapRepo.FindToReprice(chainCfg.ChainID, maxAge, after, repriceBatch)
+		if err != nil {
+			return err
+		}
+		if len(swaps) == 0 {
+			break
+		}
+
+		for _, swap := range swaps {
+			usdValue, priceAge := valuer.SwapValue(swap.Type, swap.TokenIn, swap.TokenOut, swap.Time)
+			if priceAge == nil {
+				unpriced++
+				continue
+			}
+
+			if err := swapRepo.UpdateUsdValue(swap.ID, usdValue, priceAge); err != nil {
+				return err
+			}
+			repriced++
+
+			if fromHeight == 0 || swap.Height < fromHeight {
+				fromHeight = swap.Height
+			}
+		}
+
+		after = swaps[len(swaps)-1].ID
+		log.Printf("%d swaps repriced, %d without a fresh price", repriced, unpriced)
+	}
+
+	log.Printf("repricing done: %d swaps repriced, %d without a fresh price", repriced, unpriced)
+
+	if repriced == 0 {
+		return nil
+	}
+
+	for _, module := range []string{checkpointCandles, checkpointLeaderboards} {
+		if err := rewindCheckpoint(chainCfg.ChainID, module, fromHeight-1); err != nil {
+			return err
+		}
+	}
+
+	if err := syncCandles(chainCfg, valuer); err != nil {
+		return err
+	}
+
+	if err := syncPoolStats(client, chainCfg, valuer); err != nil {
+		return err
+	}
+
+	log.Printf("the leaderboards from block %d must be rebuilt with sinfonia sync leaderboards", fromHeight)
+
+	return nil
+}
+
+// rewindCheckpoint moves the checkpoint of module back to height, a
+// checkpoint below height is left.
+func rewindCheckpoint(chainID, module string, height int64) error {
+	checkpointRepo := repository.NewCheckpointRepository()
+
+	checkpoint := checkpointRepo.Get(chainID, module)
+	if checkpoint.Height <= height {
+		return nil
+	}
+
+	return checkpointRepo.Save(checkpoint, height)
+}
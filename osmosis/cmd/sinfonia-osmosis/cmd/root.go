@@ -26,6 +26,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(
 		IndexerCmd(),
 		GetSyncCmd(),
+		GetRepriceCmd(),
 	)
 
 	return rootCmd
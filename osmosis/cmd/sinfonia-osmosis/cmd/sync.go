@@ -7,7 +7,9 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/model"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
 	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
 	"github.com/angelorc/sinfonia-go/osmosis/chain"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/v9/x/incentives/types"
@@ -21,6 +23,16 @@ import (
 	"time"
 )
 
+// checkpoint modules of the osmosis syncs
+const (
+	checkpointPools           = "pools"
+	checkpointSwaps           = "swaps"
+	checkpointIncentives      = "incentives"
+	checkpointLiquidityEvents = "liquidity_events"
+	checkpointLiquidity       = "liquidity"
+	checkpointCandles         = "candles"
+)
+
 func GetSyncCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "sync",
@@ -35,6 +47,9 @@ func GetSyncCmd() *cobra.Command {
 		GetSyncPricesCmd(),
 		GetSyncHistoricalPricesCmd(),
 		GetSyncLiquidityEventsCmd(),
+		GetSyncCandlesCmd(),
+		GetSyncLiquidityCmd(),
+		GetSyncPoolStatsCmd(),
 	)
 
 	return cmd
@@ -57,6 +72,10 @@ func GetSyncSwapCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			defaultDB := db.Database{
 				DataBaseRefName: "default",
 				URL:             cfg.Mongo.Uri,
@@ -66,7 +85,22 @@ func GetSyncSwapCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			if err := syncSwaps(); err != nil {
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			if err := syncSwaps(client, &cfg.Osmosis, valuer); err != nil {
 				return err
 			}
 
@@ -75,6 +109,7 @@ func GetSyncSwapCmd() *cobra.Command {
 	}
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
@@ -100,10 +135,7 @@ func chunkSlice(slice []modelv2.Attribute, chunkSize int) [][]modelv2.Attribute
 }
 
 func convertCoinToCoinModel(coin sdk.Coin) modelv2.Coin {
-	return modelv2.Coin{
-		Amount: coin.Amount.ToDec().MustFloat64(),
-		Denom:  coin.Denom,
-	}
+	return modelv2.MustNewCoin(coin.Denom, coin.Amount.String())
 }
 
 func convertCoinsToCoinsModel(coins []sdk.Coin) []modelv2.Coin {
@@ -117,34 +149,30 @@ func convertCoinsToCoinsModel(coins []sdk.Coin) []modelv2.Coin {
 	return output
 }
 
-func syncSwaps() error {
+func syncSwaps(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight("osmosis-1")
-	// TODO: get first available block
-	defaultBlock := 5112889
+	lastBlock := model.GetLastHeight(chainCfg.ChainID)
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	sync.One()
+	// get last block synced, the sync starts from the genesis height
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointSwaps)
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Swaps = int64(defaultBlock)
-	}
-
-	if sync.Swaps < int64(defaultBlock) {
-		sync.Swaps = int64(defaultBlock)
+	syncedBlock := checkpoint.Height
+	if genesisHeight := chainCfg.ModuleGenesisHeight(checkpointSwaps); syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
 	}
 
 	txRepo := repository.NewTransactionRepository()
 	swapRepo := repository.NewSwapRepository()
 	poolRepo := repository.NewPoolRepository()
-	hpr := repository.NewHistoricalPriceRepository()
 
 	limit := 2000
-	fromBlock := sync.Swaps + 1
+	fromBlock := syncedBlock + 1
 	toBlock := fromBlock + int64(limit)
-	batches := int(math.Ceil(float64(lastBlock-fromBlock) / float64(limit)))
+	if toBlock > lastBlock {
+		toBlock = lastBlock
+	}
+	batches := int(math.Ceil(float64(lastBlock-fromBlock+1) / float64(limit)))
 
 	log.Printf("Scanning blocks from %d to %d, batches %d, first end block %d\n", fromBlock, lastBlock, batches, toBlock)
 
@@ -156,7 +184,7 @@ func syncSwaps() error {
 		events := []bson.M{
 			{"events.type": "token_swapped"},
 		}
-		txs, err := txRepo.FindEventsByTypes(events, fromBlock, toBlock)
+		txs, err := txRepo.FindEventsByTypes(chainCfg.ChainID, events, fromBlock, toBlock)
 		log.Printf("Scanning blocks from %d to %d, %d txs founds, batch %d/%d\n", fromBlock, toBlock, len(txs), i, batches)
 
 		if err != nil {
@@ -209,18 +237,9 @@ func syncSwaps() error {
 							swapCreate.Type = 1 // sell
 						}
 
-						// add usd value
-						price := float64(0)
-						prices := hpr.FindByAsset(pool.GetQuoteAsset().Denom, tx.Time)
-						if len(prices) > 0 {
-							price = prices[0].Price
-						}
-
-						if swapCreate.Type == 0 {
-							swapCreate.UsdValue = (swapCreate.TokenOut.Amount * 0.000001) * price
-						} else {
-							swapCreate.UsdValue = (swapCreate.TokenIn.Amount * 0.000001) * price
-						}
+						// add usd value, the swap is valued on the quote side
+						swapCreate.UsdValue, swapCreate.UsdPriceAge = valuer.SwapValue(swapCreate.Type, swapCreate.TokenIn, swapCreate.TokenOut, tx.Time)
+						swapCreate.Price = valuer.SwapPrice(pool, swapCreate.TokenIn, swapCreate.TokenOut)
 
 						// save swap
 						_, err := swapRepo.Create(swapCreate)
@@ -229,18 +248,24 @@ func syncSwaps() error {
 							if !strings.Contains(err.Error(), "E11000 duplicate key error") {
 								log.Fatalf("Failed to write swap to db. Err: %s", err.Error())
 							}
+						} else {
+							pubsub.Publish(pubsub.TopicSwaps, swapCreate)
 						}
 					}
 				}
 			}
 
 			// update sync with last synced height
-			sync.Swaps = tx.Height
-			if err := sync.Save(); err != nil {
+			if err := checkpointRepo.Save(checkpoint, tx.Height); err != nil {
 				return err
 			}
 		}
 
+		// the whole batch has been scanned, even if it had no swaps
+		if err := checkpointRepo.Save(checkpoint, toBlock); err != nil {
+			return err
+		}
+
 		fromBlock = toBlock + 1
 		toBlock = fromBlock + int64(limit)
 		if toBlock > lastBlock {
@@ -248,9 +273,9 @@ func syncSwaps() error {
 		}
 	}
 
-	fmt.Printf("swaps synced to block %d", sync.Swaps)
+	fmt.Printf("swaps synced to block %d", checkpoint.Height)
 
-	return nil
+	return syncCandles(chainCfg, valuer)
 }
 
 func calcFee(tokenInStr string, swapFee float64) float64 {
@@ -261,39 +286,6 @@ func calcFee(tokenInStr string, swapFee float64) float64 {
 	return tokenIn.Amount.Sub(tokenInAfterFee).ToDec().MustFloat64()
 }
 
-func calcVolumeUSD(tokensIn, tokensOut string, ts time.Time) float64 {
-	ibcDenom := "ibc/8B066EED78CCC6A90E963C81EB4B527C28FE538BE396B8756F4C4BFC53C74221"
-	amtBTSG := int64(0)
-
-	if strings.HasSuffix(tokensIn, ibcDenom) {
-		amt := strings.Replace(tokensIn, ibcDenom, "", -1)
-		amtBTSG, _ = strconv.ParseInt(amt, 0, 64)
-	}
-
-	if strings.HasSuffix(tokensOut, ibcDenom) {
-		amt := strings.Replace(tokensOut, ibcDenom, "", -1)
-		amtBTSG, _ = strconv.ParseInt(amt, 0, 64)
-	}
-
-	prices := make(map[string]float64)
-	prices["09-05-2022"] = 0.04840136619600178
-	prices["10-05-2022"] = 0.039866432548801504
-	prices["11-05-2022"] = 0.03766485405081663
-	prices["12-05-2022"] = 0.033936829238417024
-	prices["13-05-2022"] = 0.02447988666488955
-	prices["14-05-2022"] = 0.022366606595902307
-	prices["15-05-2022"] = 0.02193695925498291
-	prices["16-05-2022"] = 0.025348193585375472
-	prices["17-05-2022"] = 0.02257440395770441
-	prices["18-05-2022"] = 0.02299961941622652
-	prices["19-05-2022"] = 0.020301519267571042
-
-	volume := (float64(amtBTSG) * 0.000001) * prices[ts.Format("02-01-2006")]
-
-	//return fmt.Sprintf("%f", volume)
-	return volume
-}
-
 func GetSyncIncentivesCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "incentives",
@@ -311,6 +303,10 @@ func GetSyncIncentivesCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			defaultDB := db.Database{
 				DataBaseRefName: "default",
 				URL:             cfg.Mongo.Uri,
@@ -325,7 +321,17 @@ func GetSyncIncentivesCmd() *cobra.Command {
 				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
 			}
 
-			if err := syncIncentives(client); err != nil {
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			if err := syncIncentives(client, &cfg.Osmosis, valuer); err != nil {
 				return err
 			}
 
@@ -334,39 +340,39 @@ func GetSyncIncentivesCmd() *cobra.Command {
 	}
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
 
-func syncIncentives(client *chain.Client) error {
+func syncIncentives(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight("osmosis-1")
-	// TODO: get first available block
-	defaultBlock := 5112889
+	lastBlock := model.GetLastHeight(chainCfg.ChainID)
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	sync.One()
+	// get last block synced, the sync starts from the genesis height
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointIncentives)
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Incentives = int64(defaultBlock)
+	syncedBlock := checkpoint.Height
+	if genesisHeight := chainCfg.ModuleGenesisHeight(checkpointIncentives); syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
 	}
 
-	if sync.Incentives < int64(defaultBlock) {
-		sync.Incentives = int64(defaultBlock)
-	}
+	incentiveRepo := repository.NewIncentiveRepository()
+	blockRepo := repository.NewBlockRepository()
 
-	fromBlock := sync.Incentives + 1
+	fromBlock := syncedBlock + 1
 
 	for height := fromBlock; height < lastBlock; height++ {
-		incentiveRepo := repository.NewIncentiveRepository()
+		height := height
+		block := blockRepo.FindOne(&types2.BlockFilter{ChainID: &chainCfg.ChainID, Height: &height})
 
 		log.Printf("querying block results, height %d", height)
 
-		ctx, _ := context.WithTimeout(context.Background(), 100*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
 
 		blockResults, err := client.QueryBlockResults(ctx, &height)
+		cancel()
 		if err != nil {
 			return fmt.Errorf("error while fetching blockresults, err: %s", err.Error())
 		}
@@ -377,9 +383,9 @@ func syncIncentives(client *chain.Client) error {
 			switch evt.Type {
 			case types.TypeEvtDistribution:
 				incentive := modelv2.IncentiveCreateReq{
-					ChainID: "osmosis-1",
+					ChainID: chainCfg.ChainID,
 					Height:  height,
-					Time:    time.Now(), // add block time
+					Time:    block.Time,
 				}
 
 				for _, attr := range evt.Attributes {
@@ -395,6 +401,8 @@ func syncIncentives(client *chain.Client) error {
 					}
 				}
 
+				incentive.UsdValue = valuer.TotalValue(incentive.Assets, block.Time)
+
 				_, err := incentiveRepo.Create(&incentive)
 				if err != nil {
 					return fmt.Errorf("error while storing incentive, err: %s", err.Error())
@@ -404,13 +412,12 @@ func syncIncentives(client *chain.Client) error {
 		}
 
 		// update sync with last synced height
-		sync.Incentives = lastBlock
-		if err := sync.Save(); err != nil {
+		if err := checkpointRepo.Save(checkpoint, height); err != nil {
 			return err
 		}
 	}
 
-	fmt.Printf("incentives synced to block %d", sync.Incentives)
+	fmt.Printf("incentives synced to block %d", checkpoint.Height)
 
 	return nil
 }
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
+	"github.com/spf13/cobra"
+)
+
+// candlesWindow is the range of swaps read at once, it is a multiple of all
+// the candle intervals so that no candle is split between two windows.
+const candlesWindow = 24 * time.Hour
+
+func GetSyncCandlesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "candles",
+		Short:   "sync the candles of the tracked pools from the synced swaps",
+		Example: "sinfonia-osmosis sync candles",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			return syncCandles(&cfg.Osmosis, valuer)
+		},
+	}
+
+	addConfigFlag(cmd)
+	addChainFlags(cmd)
+
+	return cmd
+}
+
+// syncCandles stores the candles of the intervals closed before the time of
+// the last synced swaps block. The candles of the open intervals are not
+// stored, they are built from the swaps when requested.
+//
+// The candles checkpoint is the swaps block of the last sync, only the
+// intervals from its time are built again, so that the candles of the swaps
+// synced, rolled back or repriced after a rewind of the checkpoint are
+// replaced. The candles are upserted, the rebuilt intervals left without
+// swaps are deleted once their candles are stored.
+func syncCandles(chainCfg *config.ChainConfig, valuer *usdValuer) error {
+	checkpointRepo := repository.NewCheckpointRepository()
+	swapsCheckpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointSwaps)
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointCandles)
+	if swapsCheckpoint.Height == 0 || checkpoint.Height >= swapsCheckpoint.Height {
+		return nil
+	}
+
+	horizon, err := blockTime(chainCfg.ChainID, swapsCheckpoint.Height)
+	if err != nil {
+		return err
+	}
+
+	swapRepo := repository.NewSwapRepository()
+	poolRepo := repository.NewPoolRepository()
+	candleRepo := repository.NewCandleRepository()
+
+	var synced time.Time
+	if checkpoint.Height > 0 {
+		if synced, err = blockTime(chainCfg.ChainID, checkpoint.Height); err != nil {
+			return err
+		}
+	} else {
+		if synced, err = firstSwapTime(swapRepo, chainCfg.ChainID); err != nil || synced.IsZero() {
+			return err
+		}
+	}
+
+	pools := make(map[int64]*modelv2.Pool)
+
+	for _, interval := range modelv2.CandleIntervals {
+		// the interval open at the last sync was not stored, the one open at
+		// the horizon is not stored yet
+		from, to := interval.Start(synced), interval.Start(horizon)
+
+		for start := from; start.Before(to); start = start.Add(candlesWindow) {
+			end := start.Add(candlesWindow)
+			if end.After(to) {
+				end = to
+			}
+
+			swaps, err := swapRepo.FindByTime(chainCfg.ChainID, nil, start, end)
+			if err != nil {
+				return err
+			}
+
+			poolSwaps := make(map[int64][]*modelv2.Swap)
+			for _, swap := range swaps {
+				// the swaps synced before the price was stored
+				if swap.Price == 0 {
+					pool, ok := pools[swap.PoolId]
+					if !ok {
+						poolID := uint64(swap.PoolId)
+						pool = poolRepo.FindOne(&modelv2.PoolFilter{ChainID: &chainCfg.ChainID, PoolID: &poolID})
+						pools[swap.PoolId] = pool
+					}
+					swap.Price = valuer.SwapPrice(pool, swap.TokenIn, swap.TokenOut)
+				}
+
+				poolSwaps[swap.PoolId] = append(poolSwaps[swap.PoolId], swap)
+			}
+
+			poolIDs := make([]int64, 0, len(poolSwaps))
+			for poolID := range poolSwaps {
+				poolIDs = append(poolIDs, poolID)
+			}
+			sort.Slice(poolIDs, func(i, j int) bool { return poolIDs[i] < poolIDs[j] })
+
+			candles := make([]*modelv2.Candle, 0)
+			for _, poolID := range poolIDs {
+				meta := modelv2.CandleMeta{ChainID: chainCfg.ChainID, PoolID: uint64(poolID), Interval: interval}
+
+				for _, candle := range modelv2.NewCandles(meta, poolSwaps[poolID]) {
+					candle.Height = swapsCheckpoint.Height
+					candles = append(candles, candle)
+				}
+			}
+
+			if err := candleRepo.UpsertMany(candles); err != nil {
+				return fmt.Errorf("failed to write candles to db. err: %w", err)
+			}
+
+			deleted, err := candleRepo.DeleteStale(chainCfg.ChainID, interval, start, end, swapsCheckpoint.Height)
+			if err != nil {
+				return fmt.Errorf("failed to delete candles from db. err: %w", err)
+			}
+
+			log.Printf("%d %s candles stored and %d deleted from %s", len(candles), interval, deleted, start.Format(time.RFC3339))
+		}
+	}
+
+	return checkpointRepo.Save(checkpoint, swapsCheckpoint.Height)
+}
+
+func firstSwapTime(swapRepo repository.SwapRepository, chainID string) (time.Time, error) {
+	orderBy := "time_ASC"
+	limit := int64(1)
+
+	swaps, err := swapRepo.Find(&modelv2.SwapFilter{ChainID: &chainID}, &types2.PaginationReq{OrderBy: &orderBy, Limit: &limit})
+	if err != nil || len(swaps) == 0 {
+		return time.Time{}, err
+	}
+
+	return swaps[0].Time, nil
+}
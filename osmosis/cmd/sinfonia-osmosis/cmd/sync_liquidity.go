@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
+	"github.com/spf13/cobra"
+)
+
+func GetSyncLiquidityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "liquidity",
+		Short:   "sync the liquidity history of the tracked pools from the synced liquidity events and swaps",
+		Example: "sinfonia-osmosis sync liquidity --verify",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			verify, err := cmd.Flags().GetBool(flagVerify)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			return syncLiquidity(client, &cfg.Osmosis, valuer, verify)
+		},
+	}
+
+	cmd.Flags().Bool(flagVerify, false, "cross-check the replayed reserves with the chain at the end of every batch, it requires an archive node")
+	addConfigFlag(cmd)
+	addChainFlags(cmd)
+
+	return cmd
+}
+
+// reservesChange is a change of the reserves of a pool, in is added to the
+// reserves and out is removed. shares is the change of the pool shares, nil
+// when it is unknown.
+type reservesChange struct {
+	poolID uint64
+	height int64
+	time   time.Time
+	in     []modelv2.Coin
+	out    []modelv2.Coin
+	shares *big.Int
+}
+
+// reservesChanges merges the liquidity events and the swaps, sorted by height.
+// A join has only tokens in and mints shares, an exit has only tokens out and
+// burns shares, a swap does not change the shares.
+func reservesChanges(events []*modelv2.LiquidityEvent, swaps []*modelv2.Swap) []reservesChange {
+	changes := make([]reservesChange, 0, len(events)+len(swaps))
+
+	for _, evt := range events {
+		var shares *big.Int
+		if evt.Shares != nil {
+			shares, _ = new(big.Int).SetString(evt.Shares.Amount, 10)
+			if shares != nil && evt.Type == modelv2.LiquidityEventTypeExit {
+				shares.Neg(shares)
+			}
+		}
+
+		changes = append(changes, reservesChange{
+			poolID: evt.PoolID,
+			height: evt.Height,
+			time:   evt.Time,
+			in:     evt.TokensIn,
+			out:    evt.TokensOut,
+			shares: shares,
+		})
+	}
+
+	for _, swap := range swaps {
+		changes = append(changes, reservesChange{
+			poolID: uint64(swap.PoolId),
+			height: swap.Height,
+			time:   swap.Time,
+			in:     []modelv2.Coin{swap.TokenIn},
+			out:    []modelv2.Coin{swap.TokenOut},
+			shares: new(big.Int),
+		})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].height < changes[j].height })
+
+	return changes
+}
+
+// syncLiquidity replays the reserves of the tracked pools and stores a
+// snapshot per pool and interval. The reserves start from the last snapshot of
+// the pool or, for a pool without snapshots, from the reserves at its creation
+// (or at the first synced height for the pools imported at genesis).
+func syncLiquidity(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer, verify bool) error {
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointLiquidity)
+
+	// the reserves can be replayed up to the height synced by all the modules
+	// they are built from
+	lastBlock := int64(math.MaxInt64)
+	for _, module := range []string{checkpointPools, checkpointSwaps, checkpointLiquidityEvents} {
+		if height := checkpointRepo.Get(chainCfg.ChainID, module).Height; height < lastBlock {
+			lastBlock = height
+		}
+	}
+
+	syncedBlock := checkpoint.Height
+	if genesisHeight := chainCfg.ModuleGenesisHeight(checkpointLiquidity); syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
+	}
+
+	if lastBlock <= syncedBlock {
+		fmt.Printf("liquidity synced to block %d", checkpoint.Height)
+		return nil
+	}
+
+	poolRepo := repository.NewPoolRepository()
+	swapRepo := repository.NewSwapRepository()
+	liquidityRepo := repository.NewLiquidityRepository()
+	historicalLiqRepo := repository.NewHistoricalLiquidityRepository()
+	historicalLiqRepo.EnsureIndexes()
+
+	tracked := true
+	pools, err := poolRepo.Find(&modelv2.PoolFilter{ChainID: &chainCfg.ChainID, Tracked: &tracked}, &types2.PaginationReq{})
+	if err != nil {
+		return err
+	}
+
+	saveSnapshot := func(poolID uint64, height int64, t time.Time, reserves modelv2.Reserves, shares *big.Int) error {
+		assets := reserves.Coins()
+
+		snapshot := &modelv2.HistoricalLiquidityCreateReq{
+			ChainID:  chainCfg.ChainID,
+			Height:   height,
+			PoolID:   poolID,
+			Assets:   assets,
+			UsdValue: valuer.TotalValue(assets, t),
+			Time:     t.UTC().Truncate(modelv2.HistoricalLiquidityInterval),
+		}
+		if shares != nil {
+			snapshot.TotalShares = shares.String()
+		}
+
+		return historicalLiqRepo.Upsert(snapshot)
+	}
+
+	// reserves and total shares of the pools at height synced[poolID], the
+	// changes up to it are already counted. The shares are nil when unknown.
+	reserves := make(map[uint64]modelv2.Reserves)
+	shares := make(map[uint64]*big.Int)
+	synced := make(map[uint64]int64)
+
+	for _, pool := range pools {
+		if pool.Height > lastBlock {
+			continue
+		}
+
+		latest := historicalLiqRepo.Latest(chainCfg.ChainID, pool.PoolID)
+		if !latest.ID.IsZero() {
+			reserves[pool.PoolID] = modelv2.NewReserves(latest.Assets)
+			shares[pool.PoolID] = latest.Shares()
+			synced[pool.PoolID] = latest.Height
+			continue
+		}
+
+		height := pool.Height
+		if height < syncedBlock {
+			height = syncedBlock
+		}
+
+		initial, initialShares, err := queryPoolReserves(client, pool.PoolID, height)
+		if err != nil {
+			log.Printf("failed to query pool %d at height %d, starting from the stored assets. err: %v", pool.PoolID, height, err)
+
+			initial = modelv2.NewReserves(make([]modelv2.Coin, 0))
+			for _, asset := range pool.PoolAssets {
+				initial.Add(asset.Token)
+			}
+		}
+
+		t, err := blockTime(chainCfg.ChainID, height)
+		if err != nil {
+			t = pool.Time
+		}
+
+		if err := saveSnapshot(pool.PoolID, height, t, initial, initialShares); err != nil {
+			return fmt.Errorf("failed to write liquidity of pool %d to db. err: %w", pool.PoolID, err)
+		}
+
+		reserves[pool.PoolID] = initial
+		shares[pool.PoolID] = initialShares
+		synced[pool.PoolID] = height
+	}
+
+	limit := 2500
+	fromBlock := syncedBlock + 1
+	toBlock := fromBlock + int64(limit)
+	if toBlock > lastBlock {
+		toBlock = lastBlock
+	}
+	batches := int(math.Ceil(float64(lastBlock-fromBlock+1) / float64(limit)))
+
+	log.Printf("Replaying blocks from %d to %d, batches %d, first end block %d\n", fromBlock, lastBlock, batches, toBlock)
+
+	for i := 1; i <= batches; i++ {
+		if fromBlock > toBlock {
+			continue
+		}
+
+		events, err := liquidityRepo.FindByHeightRange(chainCfg.ChainID, fromBlock, toBlock)
+		if err != nil {
+			return err
+		}
+
+		swaps, err := swapRepo.FindByHeightRange(chainCfg.ChainID, fromBlock, toBlock)
+		if err != nil {
+			return err
+		}
+
+		changes := reservesChanges(events, swaps)
+		log.Printf("Replaying blocks from %d to %d, %d changes found, batch %d/%d\n", fromBlock, toBlock, len(changes), i, batches)
+
+		// the last change of every pool not stored yet, a snapshot is stored
+		// when the pool changes in a following interval
+		pending := make(map[uint64]reservesChange)
+
+		for _, change := range changes {
+			poolReserves, ok := reserves[change.poolID]
+			if !ok || change.height <= synced[change.poolID] {
+				continue
+			}
+
+			interval := change.time.UTC().Truncate(modelv2.HistoricalLiquidityInterval)
+			if last, ok := pending[change.poolID]; ok && !last.time.UTC().Truncate(modelv2.HistoricalLiquidityInterval).Equal(interval) {
+				if err := saveSnapshot(last.poolID, last.height, last.time, poolReserves, shares[last.poolID]); err != nil {
+					return fmt.Errorf("failed to write liquidity of pool %d to db. err: %w", last.poolID, err)
+				}
+			}
+
+			poolReserves.Add(change.in...)
+			poolReserves.Sub(change.out...)
+			if poolShares := shares[change.poolID]; poolShares != nil && change.shares != nil {
+				poolShares.Add(poolShares, change.shares)
+			} else {
+				shares[change.poolID] = nil
+			}
+			pending[change.poolID] = change
+		}
+
+		for poolID, last := range pending {
+			if err := saveSnapshot(poolID, last.height, last.time, reserves[poolID], shares[poolID]); err != nil {
+				return fmt.Errorf("failed to write liquidity of pool %d to db. err: %w", poolID, err)
+			}
+		}
+
+		if verify {
+			if err := verifyReserves(client, chainCfg.ChainID, toBlock, reserves, shares, saveSnapshot); err != nil {
+				return err
+			}
+		}
+
+		if err := checkpointRepo.Save(checkpoint, toBlock); err != nil {
+			return err
+		}
+
+		fromBlock = toBlock + 1
+		toBlock = fromBlock + int64(limit)
+		if toBlock > lastBlock {
+			toBlock = lastBlock
+		}
+	}
+
+	fmt.Printf("liquidity synced to block %d", checkpoint.Height)
+
+	return nil
+}
+
+// verifyReserves compares the replayed reserves and shares with the ones of
+// the chain at height, the ones of the chain replace the replayed ones when
+// they do not match.
+func verifyReserves(
+	client *chain.Client,
+	chainID string,
+	height int64,
+	reserves map[uint64]modelv2.Reserves,
+	shares map[uint64]*big.Int,
+	saveSnapshot func(poolID uint64, height int64, t time.Time, reserves modelv2.Reserves, shares *big.Int) error,
+) error {
+	t, err := blockTime(chainID, height)
+	if err != nil {
+		return err
+	}
+
+	for poolID, replayed := range reserves {
+		onChain, onChainShares, err := queryPoolReserves(client, poolID, height)
+		if err != nil {
+			return fmt.Errorf("failed to query pool %d at height %d. err: %w", poolID, height, err)
+		}
+
+		replayedShares := shares[poolID]
+		if onChain.Equal(replayed) && replayedShares != nil && replayedShares.Cmp(onChainShares) == 0 {
+			continue
+		}
+
+		log.Printf("pool %d: replayed reserves %s and shares %v do not match the chain reserves %s and shares %s at height %d", poolID, replayed.Coins(), replayedShares, onChain.Coins(), onChainShares, height)
+
+		reserves[poolID] = onChain
+		shares[poolID] = onChainShares
+		if err := saveSnapshot(poolID, height, t, onChain, onChainShares); err != nil {
+			return fmt.Errorf("failed to write liquidity of pool %d to db. err: %w", poolID, err)
+		}
+	}
+
+	return nil
+}
+
+// queryPoolReserves returns the reserves and the total shares of a pool at
+// height.
+func queryPoolReserves(client *chain.Client, poolID uint64, height int64) (modelv2.Reserves, *big.Int, error) {
+	pool, err := queryBalancerPool(client, poolID, height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return modelv2.NewReserves(convertPoolAssetsToCoinModel(pool.GetAllPoolAssets())), pool.TotalShares.Amount.BigInt(), nil
+}
+
+func blockTime(chainID string, height int64) (time.Time, error) {
+	block := repository.NewBlockRepository().FindOne(&types2.BlockFilter{ChainID: &chainID, Height: &height})
+	if block.Time.IsZero() {
+		return time.Time{}, fmt.Errorf("block %d not found", height)
+	}
+
+	return block.Time, nil
+}
@@ -6,11 +6,12 @@ import (
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/model"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
 	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/cobra"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log"
 	"math"
 	"strconv"
@@ -34,6 +35,10 @@ func GetSyncLiquidityEventsCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			defaultDB := db.Database{
 				DataBaseRefName: "default",
 				URL:             cfg.Mongo.Uri,
@@ -43,7 +48,22 @@ func GetSyncLiquidityEventsCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			if err := syncLiquidityEvents(); err != nil {
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			if err := syncLiquidityEvents(client, &cfg.Osmosis, valuer); err != nil {
 				return err
 			}
 
@@ -52,37 +72,86 @@ func GetSyncLiquidityEventsCmd() *cobra.Command {
 	}
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
 
-func syncLiquidityEvents() error {
-	// get last available height on db
-	lastBlock := model.GetLastHeight("osmosis-1")
-	// TODO: get first available block
-	defaultBlock := 5112889
+// liquidityEventTypes are the types of the liquidity events by chain event.
+var liquidityEventTypes = map[string]string{
+	"pool_joined": modelv2.LiquidityEventTypeJoin,
+	"pool_exited": modelv2.LiquidityEventTypeExit,
+}
+
+// liquiditySharesEventTypes are the chain events minting and burning the pool
+// shares, by liquidity event type.
+var liquiditySharesEventTypes = map[string]string{
+	modelv2.LiquidityEventTypeJoin: "coinbase",
+	modelv2.LiquidityEventTypeExit: "burn",
+}
+
+// liquidityShares returns the shares of pool poolID minted or burned by the
+// message msgIndex of a tx, nil when the tx has no such event (e.g. a tx
+// indexed before the events were stored).
+func liquidityShares(events []modelv2.Event, msgIndex int, eventType string, poolID uint64) *modelv2.Coin {
+	denom := fmt.Sprintf("gamm/pool/%d", poolID)
+
+	var shares *modelv2.Coin
+	for _, evt := range events {
+		if evt.MsgIndex != msgIndex || evt.Type != liquiditySharesEventTypes[eventType] {
+			continue
+		}
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	sync.One()
+		// the attributes of the events of a message are merged, so the event
+		// can hold the amounts of several mints or burns
+		for _, attr := range evt.Attributes {
+			if attr.Key != "amount" {
+				continue
+			}
+
+			coins, err := sdk.ParseCoinsNormalized(attr.Value)
+			if err != nil {
+				continue
+			}
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.LiquidityEvents = int64(defaultBlock)
+			if amount := coins.AmountOf(denom); amount.IsPositive() {
+				coin := modelv2.MustNewCoin(denom, amount.String())
+				shares = &coin
+			}
+		}
 	}
 
-	if sync.LiquidityEvents < int64(defaultBlock) {
-		sync.LiquidityEvents = int64(defaultBlock)
+	return shares
+}
+
+func syncLiquidityEvents(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
+	// get last available height on db
+	lastBlock := model.GetLastHeight(chainCfg.ChainID)
+
+	// get last block synced, the sync starts from the genesis height
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointLiquidityEvents)
+
+	syncedBlock := checkpoint.Height
+	if genesisHeight := chainCfg.ModuleGenesisHeight(checkpointLiquidityEvents); syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
 	}
 
 	txRepo := repository.NewTransactionRepository()
 	liquidityRepo := repository.NewLiquidityRepository()
 	liquidityRepo.EnsureIndexes()
 
+	if err := backfillLiquidityEvents(liquidityRepo, chainCfg.ChainID, valuer); err != nil {
+		return err
+	}
+
 	limit := 2500
-	fromBlock := sync.LiquidityEvents + 1
+	fromBlock := syncedBlock + 1
 	toBlock := fromBlock + int64(limit)
-	batches := int(math.Ceil(float64(lastBlock-fromBlock) / float64(limit)))
+	if toBlock > lastBlock {
+		toBlock = lastBlock
+	}
+	batches := int(math.Ceil(float64(lastBlock-fromBlock+1) / float64(limit)))
 
 	log.Printf("Scanning blocks from %d to %d, batches %d, first end block %d\n", fromBlock, lastBlock, batches, toBlock)
 
@@ -95,7 +164,7 @@ func syncLiquidityEvents() error {
 			{"events.type": "pool_joined"},
 			{"events.type": "pool_exited"},
 		}
-		txs, err := txRepo.FindEventsByTypes(events, fromBlock, toBlock)
+		txs, err := txRepo.FindEventsByTypes(chainCfg.ChainID, events, fromBlock, toBlock)
 		log.Printf("Scanning blocks from %d to %d, %d txs founds, batch %d/%d\n", fromBlock, toBlock, len(txs), i, batches)
 
 		if err != nil {
@@ -104,10 +173,16 @@ func syncLiquidityEvents() error {
 
 		for _, tx := range txs {
 			for _, evt := range tx.Events {
+				eventType, ok := liquidityEventTypes[evt.Type]
+				if !ok {
+					continue
+				}
+
 				evtCreate := &modelv2.LiquidityEventCreateReq{
 					ChainID:   tx.ChainID,
 					Height:    tx.Height,
 					TxHash:    tx.Hash,
+					Type:      eventType,
 					Sender:    "",
 					TokensIn:  []modelv2.Coin{},
 					TokensOut: []modelv2.Coin{},
@@ -130,6 +205,9 @@ func syncLiquidityEvents() error {
 					}
 				}
 
+				evtCreate.Shares = liquidityShares(tx.Events, evt.MsgIndex, eventType, evtCreate.PoolID)
+				evtCreate.UsdValue = valuer.TotalValue(append(evtCreate.TokensIn, evtCreate.TokensOut...), tx.Time)
+
 				// log.Printf("PoolID: %d, TokensIn: %s, TokensOut: %s", evtCreate.PoolID, evtCreate.TokensIn, evtCreate.TokensOut)
 				_, err := liquidityRepo.Create(evtCreate)
 
@@ -137,6 +215,8 @@ func syncLiquidityEvents() error {
 					if !strings.Contains(err.Error(), "E11000 duplicate key error") {
 						log.Fatalf("Failed to write liquidity event to db. Err: %s", err.Error())
 					}
+				} else {
+					pubsub.Publish(pubsub.TopicLiquidityEvents, evtCreate)
 				}
 			}
 		}
@@ -149,12 +229,44 @@ func syncLiquidityEvents() error {
 	}
 
 	// update sync with last synced height
-	sync.LiquidityEvents = lastBlock
-	if err := sync.Save(); err != nil {
-		return err
+	if lastBlock > checkpoint.Height {
+		if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("liquidity events synced to block %d", sync.LiquidityEvents)
+	fmt.Printf("liquidity events synced to block %d", checkpoint.Height)
 
 	return nil
 }
+
+// backfillLiquidityEvents sets the type and the USD value of the events stored
+// by the previous versions of the sync.
+func backfillLiquidityEvents(liquidityRepo repository.LiquidityRepository, chainID string, valuer *usdValuer) error {
+	typed, err := liquidityRepo.SetMissingTypes(chainID)
+	if err != nil {
+		return err
+	}
+	if typed > 0 {
+		log.Printf("type set on %d liquidity events", typed)
+	}
+
+	for {
+		events, err := liquidityRepo.FindUnvalued(chainID, 1000)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, evt := range events {
+			usdValue := valuer.TotalValue(append(evt.TokensIn, evt.TokensOut...), evt.Time)
+			if err := liquidityRepo.SetUsdValue(evt.ID, usdValue); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("USD value set on %d liquidity events", len(events))
+	}
+}
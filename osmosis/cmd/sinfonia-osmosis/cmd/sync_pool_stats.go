@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	types2 "github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// poolStatsInterval is how often the pool stats are refreshed when
+	// following the chain
+	poolStatsInterval = 5 * time.Minute
+
+	// receiversBatch is the number of incentive receivers whose liquidity is
+	// read at once
+	receiversBatch = 1000
+)
+
+func GetSyncPoolStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pool-stats",
+		Short:   "compute the volume, fees, APR and price change of the tracked pools",
+		Example: "sinfonia-osmosis sync pool-stats",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgPath, err := cmd.Flags().GetString(flagConfig)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.NewConfig(cfgPath)
+			if err != nil {
+				return err
+			}
+
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
+			defaultDB := db.Database{
+				DataBaseRefName: "default",
+				URL:             cfg.Mongo.Uri,
+				DataBaseName:    cfg.Mongo.DbName,
+				RetryWrites:     strconv.FormatBool(cfg.Mongo.Retry),
+			}
+			defaultDB.Init()
+			defer defaultDB.Disconnect()
+
+			client, err := chain.NewClient(&cfg.Osmosis)
+			if err != nil {
+				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
+			}
+
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			valuer, err := newUSDValuer(client, &cfg.Osmosis, registry)
+			if err != nil {
+				return err
+			}
+
+			return syncPoolStats(client, &cfg.Osmosis, valuer)
+		},
+	}
+
+	addConfigFlag(cmd)
+	addChainFlags(cmd)
+
+	return cmd
+}
+
+// syncPoolStats computes the stats of the tracked pools at the time of the
+// last synced swaps block and replaces the stored ones.
+func syncPoolStats(client *chain.Client, chainCfg *config.ChainConfig, valuer *usdValuer) error {
+	checkpoint := repository.NewCheckpointRepository().Get(chainCfg.ChainID, checkpointSwaps)
+	if checkpoint.Height == 0 {
+		return nil
+	}
+
+	now, err := blockTime(chainCfg.ChainID, checkpoint.Height)
+	if err != nil {
+		return err
+	}
+	day := now.Add(-24 * time.Hour)
+	week := now.Add(-7 * 24 * time.Hour)
+
+	// the USD value of a raw unit of every denom at the time of the stats
+	unitValues := make(map[string]float64)
+	value := func(denom string, amount float64) float64 {
+		unitValue, ok := unitValues[denom]
+		if !ok {
+			unitValue = valuer.DenomValue(denom, 1, now)
+			unitValues[denom] = unitValue
+		}
+
+		return amount * unitValue
+	}
+
+	swapRepo := repository.NewSwapRepository()
+	historicalLiqRepo := repository.NewHistoricalLiquidityRepository()
+	statsRepo := repository.NewPoolStatsRepository()
+	statsRepo.EnsureIndexes()
+
+	tracked := true
+	pools, err := repository.NewPoolRepository().Find(&modelv2.PoolFilter{ChainID: &chainCfg.ChainID, Tracked: &tracked}, &types2.PaginationReq{})
+	if err != nil {
+		return err
+	}
+
+	stats := make(map[uint64]*modelv2.PoolStats)
+	for _, pool := range pools {
+		poolStats := &modelv2.PoolStats{ChainID: chainCfg.ChainID, PoolID: pool.PoolID, Height: checkpoint.Height, Time: now}
+
+		assets := make([]modelv2.Coin, len(pool.PoolAssets))
+		for i, asset := range pool.PoolAssets {
+			assets[i] = asset.Token
+		}
+		if latest := historicalLiqRepo.Latest(chainCfg.ChainID, pool.PoolID); !latest.ID.IsZero() {
+			assets = latest.Assets
+		}
+		for _, asset := range assets {
+			poolStats.Tvl += value(asset.Denom, asset.Float64())
+		}
+
+		last := swapRepo.FindLastPrice(int64(pool.PoolID), now)
+		previous := swapRepo.FindLastPrice(int64(pool.PoolID), day)
+		poolStats.Price = last.Price
+		if previous.Price > 0 {
+			poolStats.PriceChange24h = (last.Price/previous.Price - 1) * 100
+		}
+
+		stats[pool.PoolID] = poolStats
+	}
+
+	// the swaps of the block at now are included
+	volumes24h, err := swapRepo.Volumes(chainCfg.ChainID, day, now.Add(time.Second))
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes24h {
+		if poolStats, ok := stats[uint64(volume.PoolID)]; ok {
+			poolStats.Volume24h += volume.UsdValue
+			poolStats.Fees24h += value(volume.Denom, volume.Fee)
+		}
+	}
+
+	volumes7d, err := swapRepo.Volumes(chainCfg.ChainID, week, now.Add(time.Second))
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes7d {
+		if poolStats, ok := stats[uint64(volume.PoolID)]; ok {
+			poolStats.Volume7d += volume.UsdValue
+			poolStats.Fees7d += value(volume.Denom, volume.Fee)
+		}
+	}
+
+	if err := addPoolIncentives(chainCfg.ChainID, week, now, stats, value); err != nil {
+		return err
+	}
+
+	for _, poolStats := range stats {
+		if poolStats.Tvl > 0 {
+			poolStats.FeeApr = annualize(poolStats.Fees7d, 7) / poolStats.Tvl * 100
+			poolStats.IncentiveApr = annualize(poolStats.Incentives7d, 7) / poolStats.Tvl * 100
+		}
+
+		if err := statsRepo.Upsert(poolStats); err != nil {
+			return fmt.Errorf("failed to write stats of pool %d to db. err: %w", poolStats.PoolID, err)
+		}
+	}
+
+	log.Printf("stats of %d pools computed at block %d", len(stats), checkpoint.Height)
+
+	return nil
+}
+
+// addPoolIncentives adds to the stats the incentives distributed from time
+// from to time to. The incentives are not linked to a pool, so the incentives
+// of a receiver are split between the pools it provides liquidity to,
+// proportionally to the value of the liquidity joined and not exited.
+func addPoolIncentives(chainID string, from, to time.Time, stats map[uint64]*modelv2.PoolStats, value func(denom string, amount float64) float64) error {
+	incentives, err := repository.NewIncentiveRepository().SumByReceiver(chainID, from, to)
+	if err != nil {
+		return err
+	}
+
+	liquidityRepo := repository.NewLiquidityRepository()
+
+	for start := 0; start < len(incentives); start += receiversBatch {
+		end := start + receiversBatch
+		if end > len(incentives) {
+			end = len(incentives)
+		}
+
+		receivers := make([]string, 0, end-start)
+		for _, incentive := range incentives[start:end] {
+			receivers = append(receivers, incentive.Receiver)
+		}
+
+		balances, err := liquidityRepo.Balances(chainID, receivers)
+		if err != nil {
+			return err
+		}
+
+		// value of the liquidity of every receiver by pool
+		liquidity := make(map[string]map[uint64]float64)
+		for _, balance := range balances {
+			amount, err := strconv.ParseFloat(balance.Amount.String(), 64)
+			if err != nil {
+				continue
+			}
+
+			if liquidity[balance.Sender] == nil {
+				liquidity[balance.Sender] = make(map[uint64]float64)
+			}
+			liquidity[balance.Sender][balance.PoolID] += value(balance.Denom, amount)
+		}
+
+		for _, incentive := range incentives[start:end] {
+			total := float64(0)
+			for _, poolValue := range liquidity[incentive.Receiver] {
+				if poolValue > 0 {
+					total += poolValue
+				}
+			}
+
+			for poolID, poolValue := range liquidity[incentive.Receiver] {
+				if poolStats, ok := stats[poolID]; ok && poolValue > 0 {
+					poolStats.Incentives7d += incentive.UsdValue * poolValue / total
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// annualize returns the yearly amount of an amount earned in days.
+func annualize(amount float64, days float64) float64 {
+	return amount * 365 / days
+}
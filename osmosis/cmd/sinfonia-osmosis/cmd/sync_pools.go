@@ -13,7 +13,6 @@ import (
 	gammtypes "github.com/osmosis-labs/osmosis/v9/x/gamm/types"
 	"github.com/spf13/cobra"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"log"
 	"math"
 	"strconv"
@@ -38,6 +37,10 @@ func GetSyncPoolCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			defaultDB := db.Database{
 				DataBaseRefName: "default",
 				URL:             cfg.Mongo.Uri,
@@ -52,7 +55,12 @@ func GetSyncPoolCmd() *cobra.Command {
 				return fmt.Errorf("failed to get RPC endpoints on chain %s. err: %v", "osmosis", err)
 			}
 
-			if err := syncPools(client); err != nil {
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
+			if err := syncPools(client, &cfg.Osmosis, registry); err != nil {
 				return err
 			}
 
@@ -61,6 +69,7 @@ func GetSyncPoolCmd() *cobra.Command {
 	}
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
@@ -82,6 +91,10 @@ func GetSyncOldPoolCmd() *cobra.Command {
 				return err
 			}
 
+			if err := applyChainFlags(cmd, &cfg.Osmosis); err != nil {
+				return err
+			}
+
 			defaultDB := db.Database{
 				DataBaseRefName: "default",
 				URL:             cfg.Mongo.Uri,
@@ -99,6 +112,11 @@ func GetSyncOldPoolCmd() *cobra.Command {
 			poolRepo := repository.NewPoolRepository()
 			poolRepo.EnsureIndexes()
 
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
+			}
+
 			// historicalLiqRepo := repository.NewHistoricalLiquidityRepository()
 
 			// defaultBlock := int64(5112879)
@@ -126,32 +144,10 @@ func GetSyncOldPoolCmd() *cobra.Command {
 
 				poolAssets := pool.GetAllPoolAssets()
 
-				tracked := false
-				inverted := false
-				for i, pAsset := range poolAssets {
-					if len(poolAssets) == 2 {
-						if pAsset.Token.Denom == "uosmo" ||
-							pAsset.Token.Denom == "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" ||
-							pAsset.Token.Denom == "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452" {
-							tracked = true
-						}
-
-						if pAsset.Token.Denom == "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" {
-							if i == 0 && poolAssets[1].Token.Denom != "uosmo" {
-								inverted = true
-							}
-						}
-
-						if pAsset.Token.Denom == "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452" {
-							if i == 0 && poolAssets[1].Token.Denom != "uosmo" {
-								inverted = true
-							}
-						}
-					}
-				}
+				tracked, inverted := registry.TrackPool(poolAssetsDenoms(poolAssets))
 
 				_, err = poolRepo.Create(&modelv2.PoolCreateReq{
-					ChainID:    "osmosis-1",
+					ChainID:    cfg.Osmosis.ChainID,
 					Height:     0,
 					TxHash:     "",
 					PoolID:     uint64(i),
@@ -183,37 +179,41 @@ func GetSyncOldPoolCmd() *cobra.Command {
 	}
 
 	addConfigFlag(cmd)
+	addChainFlags(cmd)
 
 	return cmd
 }
 
-func syncPools(client *chain.Client) error {
+func syncPools(client *chain.Client, chainCfg *config.ChainConfig, registry *modelv2.AssetRegistry) error {
 	// get last available height on db
-	lastBlock := model.GetLastHeight("osmosis-1")
-	// TODO: get first available block
-	defaultBlock := 5112889
+	lastBlock := model.GetLastHeight(chainCfg.ChainID)
 
-	// get last block synced from account
-	sync := new(model.Sync)
-	sync.One()
+	// get last block synced, the sync starts from the genesis height
+	checkpointRepo := repository.NewCheckpointRepository()
+	checkpoint := checkpointRepo.Get(chainCfg.ChainID, checkpointPools)
 
-	if sync.ID.IsZero() {
-		sync.ID = primitive.NewObjectID()
-		sync.Pools = int64(defaultBlock)
-	}
-
-	if sync.Pools < int64(defaultBlock) {
-		sync.Pools = int64(defaultBlock)
+	syncedBlock := checkpoint.Height
+	if genesisHeight := chainCfg.ModuleGenesisHeight(checkpointPools); syncedBlock < genesisHeight-1 {
+		syncedBlock = genesisHeight - 1
 	}
 
 	txRepo := repository.NewTransactionRepository()
 	poolRepo := repository.NewPoolRepository()
 	// historicalLiqRepo := repository.NewHistoricalLiquidityRepository()
 
+	// the pools queried above the synced height were queried on blocks rolled
+	// back by a fork, their assets are queried again at the synced height
+	if err := refreshPools(client, poolRepo, chainCfg.ChainID, syncedBlock); err != nil {
+		return err
+	}
+
 	limit := 10000
-	fromBlock := sync.Pools + 1
+	fromBlock := syncedBlock + 1
 	toBlock := fromBlock + int64(limit)
-	batches := int(math.Ceil(float64(lastBlock-fromBlock) / float64(limit)))
+	if toBlock > lastBlock {
+		toBlock = lastBlock
+	}
+	batches := int(math.Ceil(float64(lastBlock-fromBlock+1) / float64(limit)))
 
 	log.Printf("Scanning blocks from %d to %d, batches %d, first end block %d\n", fromBlock, lastBlock, batches, toBlock)
 
@@ -226,7 +226,7 @@ func syncPools(client *chain.Client) error {
 		events := []bson.M{
 			{"events.type": "pool_created"},
 		}
-		txs, err := txRepo.FindEventsByTypes(events, fromBlock, toBlock)
+		txs, err := txRepo.FindEventsByTypes(chainCfg.ChainID, events, fromBlock, toBlock)
 		log.Printf("Scanning blocks from %d to %d, %d txs founds, batch %d/%d\n", fromBlock, toBlock, len(txs), i, batches)
 
 		if err != nil {
@@ -243,59 +243,36 @@ func syncPools(client *chain.Client) error {
 				}
 				log.Printf("pool_id %d", poolID)
 
-				poolRes, err := client.QueryPoolByID(poolID)
+				// the pool is queried at its creation, or at the last indexed
+				// block when the node does not keep the state of that height
+				assetsHeight := tx.Height
+				pool, err := queryBalancerPool(client, poolID, assetsHeight)
 				if err != nil {
-					return fmt.Errorf("error while fetching poolID, err: %s", err.Error())
-				}
+					log.Printf("failed to query pool %d at height %d, querying height %d. err: %v", poolID, assetsHeight, lastBlock, err)
 
-				var poolI gammtypes.PoolI
-				err = client.Codec.Marshaler.UnpackAny(poolRes.GetPool(), &poolI)
-				if err != nil {
-					log.Fatalf("error while decoding the new pool")
+					assetsHeight = lastBlock
+					pool, err = queryBalancerPool(client, poolID, assetsHeight)
 				}
-
-				pool, ok := poolI.(*balancer.Pool)
-				if !ok {
-					log.Fatalf("error while decoding the new pool")
+				if err != nil {
+					return fmt.Errorf("error while fetching poolID, err: %s", err.Error())
 				}
 
 				poolAssets := pool.GetAllPoolAssets()
 
-				tracked := false
-				inverted := false
-				for i, pAsset := range poolAssets {
-					if len(poolAssets) == 2 {
-						if pAsset.Token.Denom == "uosmo" ||
-							pAsset.Token.Denom == "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" ||
-							pAsset.Token.Denom == "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452" {
-							tracked = true
-						}
-
-						if pAsset.Token.Denom == "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2" {
-							if i == 0 && poolAssets[1].Token.Denom != "uosmo" {
-								inverted = true
-							}
-						}
-
-						if pAsset.Token.Denom == "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452" {
-							if i == 0 && poolAssets[1].Token.Denom != "uosmo" {
-								inverted = true
-							}
-						}
-					}
-				}
+				tracked, inverted := registry.TrackPool(poolAssetsDenoms(poolAssets))
 
 				_, err = poolRepo.Create(&modelv2.PoolCreateReq{
-					ChainID:    "osmosis-1",
-					Height:     tx.Height,
-					TxHash:     tx.Hash,
-					PoolID:     poolID,
-					PoolAssets: convertPoolAssetsToModel(pool.GetAllPoolAssets()),
-					SwapFee:    pool.GetSwapFee(sdk.Context{}).MustFloat64(),
-					ExitFee:    pool.GetExitFee(sdk.Context{}).MustFloat64(),
-					Time:       tx.Time,
-					Tracked:    tracked,
-					Inverted:   inverted,
+					ChainID:      chainCfg.ChainID,
+					Height:       tx.Height,
+					TxHash:       tx.Hash,
+					PoolID:       poolID,
+					PoolAssets:   convertPoolAssetsToModel(pool.GetAllPoolAssets()),
+					SwapFee:      pool.GetSwapFee(sdk.Context{}).MustFloat64(),
+					ExitFee:      pool.GetExitFee(sdk.Context{}).MustFloat64(),
+					AssetsHeight: assetsHeight,
+					Time:         tx.Time,
+					Tracked:      tracked,
+					Inverted:     inverted,
 				})
 
 				/*if tracked {
@@ -321,22 +298,69 @@ func syncPools(client *chain.Client) error {
 	}
 
 	// update sync with last synced height
-	sync.Pools = lastBlock
-	if err := sync.Save(); err != nil {
+	if lastBlock > checkpoint.Height {
+		if err := checkpointRepo.Save(checkpoint, lastBlock); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("pools synced to block %d", checkpoint.Height)
+
+	return nil
+}
+
+// refreshPools queries again at height the pools whose assets were queried
+// above it.
+func refreshPools(client *chain.Client, poolRepo repository.PoolRepository, chainID string, height int64) error {
+	pools, err := poolRepo.FindAssetsAboveHeight(chainID, height)
+	if err != nil {
 		return err
 	}
 
-	fmt.Printf("pools synced to block %d", sync.Pools)
+	for _, p := range pools {
+		pool, err := queryBalancerPool(client, p.PoolID, height)
+		if err != nil {
+			return fmt.Errorf("failed to query pool %d at height %d. err: %w", p.PoolID, height, err)
+		}
+
+		swapFee := pool.GetSwapFee(sdk.Context{}).MustFloat64()
+		exitFee := pool.GetExitFee(sdk.Context{}).MustFloat64()
+		if err := poolRepo.UpdateAssets(p.ID, convertPoolAssetsToModel(pool.GetAllPoolAssets()), swapFee, exitFee, height); err != nil {
+			return fmt.Errorf("failed to write pool %d to db. err: %w", p.PoolID, err)
+		}
+
+		log.Printf("pool %d queried at height %d, replacing the assets queried at height %d", p.PoolID, height, p.AssetsHeight)
+	}
 
 	return nil
 }
 
+// queryBalancerPool returns the state of a balancer pool at height.
+func queryBalancerPool(client *chain.Client, poolID uint64, height int64) (*balancer.Pool, error) {
+	poolRes, err := client.QueryPoolByIDWithHeight(poolID, height)
+	if err != nil {
+		return nil, err
+	}
+
+	var poolI gammtypes.PoolI
+	if err := client.Codec.Marshaler.UnpackAny(poolRes.GetPool(), &poolI); err != nil {
+		return nil, err
+	}
+
+	pool, ok := poolI.(*balancer.Pool)
+	if !ok {
+		return nil, fmt.Errorf("pool %d is not a balancer pool", poolID)
+	}
+
+	return pool, nil
+}
+
 func convertPoolAssetsToModel(pa []balancer.PoolAsset) []modelv2.PoolAsset {
 	newPoolAssets := make([]modelv2.PoolAsset, len(pa))
 
 	for i, p := range pa {
 		newPoolAssets[i] = modelv2.PoolAsset{
-			Token:  modelv2.Coin{Denom: p.Token.Denom, Amount: p.Token.Amount.ToDec().MustFloat64()},
+			Token:  convertCoinToCoinModel(p.Token),
 			Weight: p.Weight.String(),
 		}
 	}
@@ -348,7 +372,7 @@ func convertPoolAssetsToCoinModel(pa []balancer.PoolAsset) []modelv2.Coin {
 	newAssets := make([]modelv2.Coin, len(pa))
 
 	for i, p := range pa {
-		newAssets[i] = modelv2.Coin{Denom: p.Token.Denom, Amount: p.Token.Amount.ToDec().MustFloat64()}
+		newAssets[i] = convertCoinToCoinModel(p.Token)
 	}
 
 	return newAssets
@@ -1,11 +1,12 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/angelorc/sinfonia-go/config"
 	"github.com/angelorc/sinfonia-go/mongo/db"
 	"github.com/angelorc/sinfonia-go/mongo/modelv2"
 	"github.com/angelorc/sinfonia-go/mongo/repository"
-	"github.com/angelorc/sinfonia-go/utility"
+	"github.com/angelorc/sinfonia-go/osmosis/prices"
 	"github.com/spf13/cobra"
 	"log"
 	"strconv"
@@ -16,7 +17,7 @@ import (
 func GetSyncPricesCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "prices",
-		Short:   "sync atom, osmo and btsg current prices from coingecko",
+		Short:   "sync current prices of the tracked assets from the price sources",
 		Example: "sinfonia-osmosis prices",
 		Args:    cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -39,52 +40,66 @@ func GetSyncPricesCmd() *cobra.Command {
 			defaultDB.Init()
 			defer defaultDB.Disconnect()
 
-			hpr := repository.NewHistoricalPriceRepository()
-
-			assets := map[string]string{
-				"osmosis": "uosmo",
-				"cosmos":  "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2",
-				"bitsong": "ibc/4E5444C35610CC76FC94E7F7886B93121175C28262DDFDDE6F84E82BF2425452",
+			registry, err := loadAssetRegistry(&cfg.Osmosis)
+			if err != nil {
+				return err
 			}
 
-			startTime := time.Now().Add(-4 * time.Hour)
-			endTime := time.Now()
+			return syncPrices(&cfg.Osmosis, registry, time.Now().Add(-4*time.Hour), time.Now())
+		},
+	}
 
-			log.Printf("getting historical prices from %s to %s", startTime.Format("02-01-2006"), endTime.Format("02-01-2006"))
+	addConfigFlag(cmd)
 
-			for k, v := range assets {
-				log.Printf("getting price for %s from coingecko, time: %s", k, startTime.Format("02-01-2006"))
+	return cmd
+}
 
-				// get price
-				prices, err := utility.GetHistoricalCoinPrice(k, "usd", startTime, endTime)
-				if err != nil {
-					log.Fatal(err)
-				}
+// syncPrices stores the prices of the assets of registry from time from to
+// time to, every asset is priced by the first source having its prices. All
+// the assets are synced before reporting the ones without a price.
+func syncPrices(chainCfg *config.ChainConfig, registry *modelv2.AssetRegistry, from, to time.Time) error {
+	oracle, err := prices.NewOracleFromConfig(chainCfg)
+	if err != nil {
+		return err
+	}
 
-				for _, price := range prices {
-					_, err = hpr.Create(&modelv2.HistoricalPriceCreateReq{
-						Asset: v,
-						Price: price[1],
-						Time:  time.Unix(int64(price[0]/1000), 0).UTC(),
-					})
-
-					if err != nil {
-						if !strings.Contains(err.Error(), "E11000 duplicate key error") {
-							return err
-						}
-					} else {
-						log.Printf("stored price for %s from coingecko, time: %s, price: %2f", k, startTime.Format("02-01-2006"), price)
-					}
-				}
+	hpr := repository.NewHistoricalPriceRepository()
+
+	log.Printf("getting historical prices from %s to %s", from.Format("02-01-2006"), to.Format("02-01-2006"))
+
+	failed := make([]string, 0)
+	for _, asset := range registry.All() {
+		points, source, err := oracle.History(asset, from, to)
+		if err != nil {
+			log.Printf("failed to get the prices of %s. err: %v", asset.Symbol, err)
+			failed = append(failed, asset.Symbol)
+			continue
+		}
 
-				time.Sleep(5 * time.Second)
+		stored := 0
+		for _, point := range points {
+			_, err = hpr.Create(&modelv2.HistoricalPriceCreateReq{
+				Asset:  asset.Denom,
+				Price:  point.Price,
+				Source: source,
+				Time:   point.Time.UTC(),
+			})
+
+			if err != nil {
+				if !strings.Contains(err.Error(), "E11000 duplicate key error") {
+					return err
+				}
+			} else {
+				stored++
 			}
+		}
 
-			return nil
-		},
+		log.Printf("stored %d prices of %s from %s", stored, asset.Symbol, source)
 	}
 
-	addConfigFlag(cmd)
+	if len(failed) > 0 {
+		return fmt.Errorf("no price source has the prices of %s", strings.Join(failed, ", "))
+	}
 
-	return cmd
+	return nil
 }
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/osmosis/chain"
+	"github.com/angelorc/sinfonia-go/osmosis/prices"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// defaultDecimals is used when the decimals of a denom can not be resolved,
+// most of the cosmos assets are micro denoms.
+const defaultDecimals = 6
+
+// decimalsResolver resolves the decimals of a denom from the asset registry,
+// the denom metadata of the bank module or, for an ibc denom, the registry
+// entry of the base denom of its trace. Resolved decimals are cached, the
+// chain is queried once per denom.
+type decimalsResolver struct {
+	client   *chain.Client
+	registry *modelv2.AssetRegistry
+	cache    map[string]int
+}
+
+func newDecimalsResolver(client *chain.Client, registry *modelv2.AssetRegistry) *decimalsResolver {
+	return &decimalsResolver{
+		client:   client,
+		registry: registry,
+		cache:    make(map[string]int),
+	}
+}
+
+func (r *decimalsResolver) Decimals(denom string) int {
+	if decimals, ok := r.cache[denom]; ok {
+		return decimals
+	}
+
+	decimals, ok := r.resolve(denom)
+	if !ok {
+		log.Printf("unable to resolve decimals of %s, using %d", denom, defaultDecimals)
+		decimals = defaultDecimals
+	}
+
+	r.cache[denom] = decimals
+	return decimals
+}
+
+func (r *decimalsResolver) resolve(denom string) (int, bool) {
+	if asset, ok := r.registry.Get(denom); ok {
+		return asset.Decimals, true
+	}
+
+	if res, err := r.client.QueryDenomMetadata(denom); err == nil {
+		if decimals, ok := decimalsFromMetadata(res.Metadata); ok {
+			return decimals, true
+		}
+	}
+
+	if strings.HasPrefix(denom, "ibc/") {
+		res, err := r.client.QueryIBCDenomTrace(strings.TrimPrefix(denom, "ibc/"))
+		if err != nil {
+			log.Printf("failed to query denom trace of %s. err: %v", denom, err)
+			return 0, false
+		}
+
+		if asset, ok := r.registry.Get(res.DenomTrace.BaseDenom); ok {
+			return asset.Decimals, true
+		}
+
+		log.Printf("unknown base denom %s of %s, add it to the asset registry", res.DenomTrace.BaseDenom, denom)
+	}
+
+	return 0, false
+}
+
+// decimalsFromMetadata returns the exponent of the display unit, or the
+// highest exponent when the display unit is not listed.
+func decimalsFromMetadata(metadata banktypes.Metadata) (int, bool) {
+	if len(metadata.DenomUnits) == 0 {
+		return 0, false
+	}
+
+	exponent := uint32(0)
+	for _, unit := range metadata.DenomUnits {
+		if unit.Denom == metadata.Display {
+			return int(unit.Exponent), true
+		}
+
+		if unit.Exponent > exponent {
+			exponent = unit.Exponent
+		}
+	}
+
+	return int(exponent), exponent > 0
+}
+
+// usdValuer converts raw amounts to USD using the resolved decimals of the
+// denom and the historical price at the given time. The prices missing in
+// the historical prices, or older than the max age, are asked to the price
+// oracle and stored.
+type usdValuer struct {
+	decimals *decimalsResolver
+	registry *modelv2.AssetRegistry
+	prices   repository.HistoricalPriceRepository
+	lookup   modelv2.PriceLookup
+	oracle   *prices.Oracle
+	// misses are the denoms and hours the oracle has no price for
+	misses map[string]bool
+}
+
+func newUSDValuer(client *chain.Client, chainCfg *config.ChainConfig, registry *modelv2.AssetRegistry) (*usdValuer, error) {
+	oracle, err := prices.NewOracleFromConfig(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAge, err := chainCfg.Prices.MaxPriceAge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &usdValuer{
+		decimals: newDecimalsResolver(client, registry),
+		registry: registry,
+		prices:   repository.NewHistoricalPriceRepository(),
+		lookup:   modelv2.PriceLookup{MaxAge: maxAge, Interpolate: chainCfg.Prices.Interpolate},
+		oracle:   oracle,
+		misses:   make(map[string]bool),
+	}, nil
+}
+
+// Amount returns the amount of coin in display units.
+func (v *usdValuer) Amount(coin modelv2.Coin) float64 {
+	return coin.Float64() / math.Pow10(v.decimals.Decimals(coin.Denom))
+}
+
+// Value returns the USD value of coin at time t, 0 when no price is available.
+func (v *usdValuer) Value(coin modelv2.Coin, t time.Time) float64 {
+	return v.DenomValue(coin.Denom, coin.Float64(), t)
+}
+
+// DenomValue returns the USD value of amount raw units of denom at time t, 0
+// when no price is available.
+func (v *usdValuer) DenomValue(denom string, amount float64, t time.Time) float64 {
+	value, _ := v.QuotedValue(denom, amount, t)
+	return value
+}
+
+// QuotedValue returns the USD value of amount raw units of denom at time t and
+// the quote of the price used, the quote is nil when no price is available.
+func (v *usdValuer) QuotedValue(denom string, amount float64, t time.Time) (float64, *modelv2.PriceQuote) {
+	quote, ok := v.Quote(denom, t)
+	if !ok {
+		return 0, nil
+	}
+
+	return amount / math.Pow10(v.decimals.Decimals(denom)) * quote.Price, quote
+}
+
+// SwapValue returns the USD value of a swap, valued on the quote side, and
+// the age in seconds of the price used, nil when no price is available.
+func (v *usdValuer) SwapValue(swapType int, tokenIn, tokenOut modelv2.Coin, t time.Time) (float64, *int64) {
+	coin := tokenIn
+	if swapType == 0 {
+		coin = tokenOut
+	}
+
+	value, quote := v.QuotedValue(coin.Denom, coin.Float64(), t)
+	if quote == nil {
+		return 0, nil
+	}
+
+	age := int64(quote.Age.Seconds())
+	return value, &age
+}
+
+// Quote returns the USD price of denom at time t from the historical prices,
+// or from the oracle when none is fresh enough. A missing price is logged once
+// per denom and hour.
+func (v *usdValuer) Quote(denom string, t time.Time) (*modelv2.PriceQuote, bool) {
+	if quote, ok := v.prices.Lookup(denom, t, v.lookup); ok {
+		return quote, true
+	}
+
+	missKey := fmt.Sprintf("%s/%d", denom, t.Unix()/3600)
+	if v.misses[missKey] {
+		return nil, false
+	}
+
+	asset, ok := v.registry.Get(denom)
+	if !ok {
+		asset = &modelv2.Asset{Denom: denom}
+	}
+
+	point, source, err := v.oracle.Price(asset, t)
+	if err == nil {
+		// the sample of a source can be as old as the stored ones
+		quote, ok := v.lookup.Quote(denom, &modelv2.HistoricalPrice{Price: point.Price, Time: point.Time, Source: source}, nil, t)
+		if !ok {
+			err = fmt.Errorf("the %s price at %s is older than %s", source, point.Time.Format(time.RFC3339), v.lookup.MaxAge)
+		} else {
+			v.store(denom, point, source)
+			return quote, true
+		}
+	}
+
+	log.Printf("unable to price %s at %s, it is valued 0. err: %v", denom, t.Format(time.RFC3339), err)
+	v.misses[missKey] = true
+
+	return nil, false
+}
+
+// store adds a price of the oracle to the historical prices, so that the next
+// values of the denom do not query the oracle again.
+func (v *usdValuer) store(denom string, point prices.Point, source string) {
+	_, err := v.prices.Create(&modelv2.HistoricalPriceCreateReq{
+		Asset:  denom,
+		Price:  point.Price,
+		Source: source,
+		Time:   point.Time.UTC(),
+	})
+	if err != nil && !strings.Contains(err.Error(), "E11000 duplicate key error") {
+		log.Printf("failed to store the price of %s at %s. err: %v", denom, point.Time.Format(time.RFC3339), err)
+	}
+}
+
+// TotalValue returns the USD value of coins at time t, coins without a price
+// are not counted.
+func (v *usdValuer) TotalValue(coins []modelv2.Coin, t time.Time) float64 {
+	total := float64(0)
+	for _, coin := range coins {
+		total += v.Value(coin, t)
+	}
+
+	return total
+}
+
+// SwapPrice returns the price of the base asset of pool in quote asset from
+// the amounts exchanged by a swap, 0 for the pools that are not tracked.
+func (v *usdValuer) SwapPrice(pool *modelv2.Pool, tokenIn, tokenOut modelv2.Coin) float64 {
+	base := pool.GetBaseAsset()
+	if base == nil {
+		return 0
+	}
+
+	baseCoin, quoteCoin := tokenIn, tokenOut
+	if tokenOut.Denom == base.Denom {
+		baseCoin, quoteCoin = tokenOut, tokenIn
+	}
+
+	baseAmount := v.Amount(baseCoin)
+	if baseAmount == 0 {
+		return 0
+	}
+
+	return v.Amount(quoteCoin) / baseAmount
+}
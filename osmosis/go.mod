@@ -6,6 +6,7 @@ require (
 	github.com/angelorc/sinfonia-go/indexer v0.0.0-20220708181003-49cc9d301e3e
 	github.com/angelorc/sinfonia-go/mongo v0.0.0-20220529210934-1588298a3c64
 	github.com/angelorc/sinfonia-go/tendermint v0.0.0-20220526162529-4e6e72a126c6
+	github.com/angelorc/sinfonia-go/utility v0.0.0-20220529210934-1588298a3c64
 	github.com/cosmos/cosmos-sdk v0.45.4
 	github.com/osmosis-labs/osmosis/v9 v9.0.0
 	github.com/spf13/cobra v1.4.0
@@ -25,7 +26,6 @@ require (
 	github.com/DataDog/zstd v1.4.5 // indirect
 	github.com/Workiva/go-datastructures v1.0.53 // indirect
 	github.com/angelorc/sinfonia-go/server v0.0.0-20220708181003-49cc9d301e3e // indirect
-	github.com/angelorc/sinfonia-go/utility v0.0.0-20220529210934-1588298a3c64 // indirect
 	github.com/armon/go-metrics v0.3.10 // indirect
 	github.com/avast/retry-go v3.0.0+incompatible // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
@@ -0,0 +1,64 @@
+package prices
+
+import (
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+// coingeckoLookback is the range searched for the price of a time, the
+// samples of the older days are hourly or daily.
+const coingeckoLookback = 24 * time.Hour
+
+// coingeckoSource prices the assets having a coingecko id.
+type coingeckoSource struct {
+	client *utility.CoingeckoClient
+}
+
+func NewCoingeckoSource(client *utility.CoingeckoClient) Source {
+	return &coingeckoSource{client: client}
+}
+
+func (s *coingeckoSource) Name() string {
+	return config.PriceSourceCoingecko
+}
+
+// Price returns the last sample up to time t.
+func (s *coingeckoSource) Price(asset *modelv2.Asset, t time.Time) (Point, error) {
+	points, err := s.History(asset, t.Add(-coingeckoLookback), t)
+	if err != nil {
+		return Point{}, err
+	}
+	if len(points) == 0 {
+		return Point{}, ErrNoPrice
+	}
+
+	return points[len(points)-1], nil
+}
+
+func (s *coingeckoSource) History(asset *modelv2.Asset, from, to time.Time) ([]Point, error) {
+	if asset.CoingeckoID == "" {
+		return nil, ErrNoPrice
+	}
+
+	samples, err := s.client.History(asset.CoingeckoID, "usd", from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, 0, len(samples))
+	for _, sample := range samples {
+		if sample[1] <= 0 {
+			continue
+		}
+
+		points = append(points, Point{
+			Time:  time.UnixMilli(int64(sample[0])).UTC(),
+			Price: sample[1],
+		})
+	}
+
+	return points, nil
+}
@@ -0,0 +1,134 @@
+package prices
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+)
+
+var t0 = time.Date(2022, 7, 11, 0, 0, 0, 0, time.UTC)
+
+func at(minutes int) time.Time {
+	return t0.Add(time.Duration(minutes) * time.Minute)
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestTimeWeighted(t *testing.T) {
+	points := []Point{
+		{Time: at(-10), Price: 1},
+		{Time: at(15), Price: 2},
+		{Time: at(45), Price: 4},
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		price    float64
+		ok       bool
+	}{
+		// 15m at 1, 30m at 2, 15m at 4
+		{"window", at(0), at(60), (15*1 + 30*2 + 15*4) / 60.0, true},
+		{"no swap in window", at(50), at(60), 4, true},
+		{"spot", at(30), at(30), 2, true},
+		{"spot on a swap", at(45), at(45), 4, true},
+		// the time before the first price is not counted
+		{"before the first price", at(-20), at(-5), 1, true},
+		{"no price", at(-30), at(-20), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := TimeWeighted(points, tt.from, tt.to)
+			if ok != tt.ok || !almostEqual(price, tt.price) {
+				t.Errorf("TimeWeighted() = %v, %v, want %v, %v", price, ok, tt.price, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTimeWeightedSeries(t *testing.T) {
+	points := []Point{
+		{Time: at(10), Price: 1},
+		{Time: at(90), Price: 3},
+	}
+
+	series := TimeWeightedSeries(points, at(0), at(180), time.Hour, time.Hour)
+
+	want := []Point{
+		// from 10m, the time before the first swap is not counted
+		{Time: at(60), Price: 1},
+		{Time: at(120), Price: 2},
+		{Time: at(180), Price: 3},
+	}
+	if len(series) != len(want) {
+		t.Fatalf("TimeWeightedSeries() returned %d points, want %d", len(series), len(want))
+	}
+	for i := range want {
+		if !series[i].Time.Equal(want[i].Time) || !almostEqual(series[i].Price, want[i].Price) {
+			t.Errorf("point %d = %v, want %v", i, series[i], want[i])
+		}
+	}
+}
+
+type failingSource struct{}
+
+func (s failingSource) Name() string { return "failing" }
+
+func (s failingSource) Price(asset *modelv2.Asset, t time.Time) (Point, error) {
+	return Point{}, errors.New("unavailable")
+}
+
+func (s failingSource) History(asset *modelv2.Asset, from, to time.Time) ([]Point, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestOracle(t *testing.T) {
+	oracle := NewOracle(
+		failingSource{},
+		NewStaticSource(map[string]float64{"uusdc": 1}),
+		NewStaticSource(map[string]float64{"uusdc": 2, "uosmo": 0.5}),
+	)
+
+	point, source, err := oracle.Price(&modelv2.Asset{Denom: "uusdc"}, t0)
+	if err != nil || point.Price != 1 || source != "static" || !point.Time.Equal(t0) {
+		t.Errorf("Price(uusdc) = %v, %s, %v, want 1 from the first static source", point, source, err)
+	}
+
+	point, _, err = oracle.Price(&modelv2.Asset{Denom: "uosmo"}, t0)
+	if err != nil || point.Price != 0.5 {
+		t.Errorf("Price(uosmo) = %v, %v, want 0.5 from the second static source", point, err)
+	}
+
+	points, _, err := oracle.History(&modelv2.Asset{Denom: "uosmo"}, at(-60), t0)
+	if err != nil || len(points) != 1 || !points[0].Time.Equal(t0) {
+		t.Errorf("History(uosmo) = %v, %v, want a point at the end of the range", points, err)
+	}
+
+	_, _, err = oracle.Price(&modelv2.Asset{Denom: "uatom"}, t0)
+	if !errors.Is(err, ErrNoPrice) {
+		t.Errorf("Price(uatom) error = %v, want ErrNoPrice", err)
+	}
+}
+
+func TestLoadStaticPrices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	if err := os.WriteFile(path, []byte(`{"uusdc": 1, "uosmo": 0.75}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	prices, err := LoadStaticPrices(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prices["uusdc"] != 1 || prices["uosmo"] != 0.75 {
+		t.Errorf("LoadStaticPrices() = %v", prices)
+	}
+}
@@ -0,0 +1,140 @@
+package prices
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+// ErrNoPrice is returned when a source has no price for an asset.
+var ErrNoPrice = errors.New("no price")
+
+// Point is the USD price of an asset at a time.
+type Point struct {
+	Time  time.Time
+	Price float64
+}
+
+// Source provides the USD prices of the assets.
+type Source interface {
+	Name() string
+	// Price returns the price of asset at time t, the time of the point is
+	// the time of the sample the price comes from.
+	Price(asset *modelv2.Asset, t time.Time) (Point, error)
+	// History returns the prices of asset from time from to time to, sorted
+	// by time.
+	History(asset *modelv2.Asset, from, to time.Time) ([]Point, error)
+}
+
+// Oracle queries the sources by priority, the first source having a price
+// for an asset wins.
+type Oracle struct {
+	sources []Source
+}
+
+func NewOracle(sources ...Source) *Oracle {
+	return &Oracle{sources: sources}
+}
+
+// NewOracleFromConfig returns the oracle with the price sources of the chain.
+func NewOracleFromConfig(chainCfg *config.ChainConfig) (*Oracle, error) {
+	pricesCfg := chainCfg.Prices
+	sources := make([]Source, 0)
+
+	for _, name := range pricesCfg.PriceSources() {
+		switch name {
+		case config.PriceSourceCoingecko:
+			client := utility.NewCoingeckoClient(pricesCfg.Coingecko.APIKey, pricesCfg.Coingecko.RateLimit)
+			sources = append(sources, NewCoingeckoSource(client))
+
+		case config.PriceSourceTWAP:
+			window, err := pricesCfg.TWAPWindow()
+			if err != nil {
+				return nil, err
+			}
+
+			pools := make(map[string]uint64)
+			for _, pool := range pricesCfg.TWAP.Pools {
+				pools[pool.Denom] = pool.PoolID
+			}
+			sources = append(sources, NewTWAPSource(chainCfg.ChainID, window, pools))
+
+		case config.PriceSourceStatic:
+			prices := make(map[string]float64)
+			if pricesCfg.StaticFile != "" {
+				filePrices, err := LoadStaticPrices(pricesCfg.StaticFile)
+				if err != nil {
+					return nil, err
+				}
+				for denom, price := range filePrices {
+					prices[denom] = price
+				}
+			}
+			for denom, price := range pricesCfg.Static {
+				prices[denom] = price
+			}
+			sources = append(sources, NewStaticSource(prices))
+
+		default:
+			return nil, fmt.Errorf("unknown price source %s", name)
+		}
+	}
+
+	return NewOracle(sources...), nil
+}
+
+// Price returns the price of asset at time t and the name of the source
+// providing it. The error wraps ErrNoPrice and reports the failure of every
+// source.
+func (o *Oracle) Price(asset *modelv2.Asset, t time.Time) (Point, string, error) {
+	failures := make([]string, 0)
+
+	for _, source := range o.sources {
+		point, err := source.Price(asset, t)
+		if err == nil && point.Price > 0 {
+			return point, source.Name(), nil
+		}
+
+		failures = append(failures, failure(source, err))
+	}
+
+	return Point{}, "", noPriceError(asset, failures)
+}
+
+// History returns the prices of asset from time from to time to of the first
+// source having at least a price, and the name of the source.
+func (o *Oracle) History(asset *modelv2.Asset, from, to time.Time) ([]Point, string, error) {
+	failures := make([]string, 0)
+
+	for _, source := range o.sources {
+		points, err := source.History(asset, from, to)
+		if err == nil && len(points) > 0 {
+			return points, source.Name(), nil
+		}
+
+		failures = append(failures, failure(source, err))
+	}
+
+	return nil, "", noPriceError(asset, failures)
+}
+
+func failure(source Source, err error) string {
+	if err == nil {
+		err = ErrNoPrice
+	}
+
+	return fmt.Sprintf("%s: %v", source.Name(), err)
+}
+
+func noPriceError(asset *modelv2.Asset, failures []string) error {
+	if len(failures) == 0 {
+		return fmt.Errorf("%w for %s: no price source", ErrNoPrice, asset.Denom)
+	}
+
+	return fmt.Errorf("%w for %s (%s)", ErrNoPrice, asset.Denom, strings.Join(failures, "; "))
+}
@@ -0,0 +1,59 @@
+package prices
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+)
+
+// staticSource prices the assets with fixed prices by denom, it is meant for
+// the USD-pegged assets and for the tests.
+type staticSource struct {
+	prices map[string]float64
+}
+
+func NewStaticSource(prices map[string]float64) Source {
+	return &staticSource{prices: prices}
+}
+
+// LoadStaticPrices reads a json file mapping the denoms to their price.
+func LoadStaticPrices(path string) (map[string]float64, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var prices map[string]float64
+	if err := json.Unmarshal(bz, &prices); err != nil {
+		return nil, fmt.Errorf("invalid static prices file %s: %w", path, err)
+	}
+
+	return prices, nil
+}
+
+func (s *staticSource) Name() string {
+	return config.PriceSourceStatic
+}
+
+func (s *staticSource) Price(asset *modelv2.Asset, t time.Time) (Point, error) {
+	price, ok := s.prices[asset.Denom]
+	if !ok || price <= 0 {
+		return Point{}, ErrNoPrice
+	}
+
+	return Point{Time: t, Price: price}, nil
+}
+
+// History returns the fixed price at time to.
+func (s *staticSource) History(asset *modelv2.Asset, from, to time.Time) ([]Point, error) {
+	point, err := s.Price(asset, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Point{point}, nil
+}
@@ -0,0 +1,202 @@
+package prices
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+)
+
+const (
+	// twapSpotStep is the step of the history of a spot price
+	twapSpotStep = time.Hour
+	// twapChunk is the range of the swaps loaded at once to build a history
+	twapChunk = 24 * time.Hour
+)
+
+// twapSource prices the assets from the indexed swaps of the tracked pools
+// pairing them with a USD-pegged asset. The price is the time-weighted average
+// of the swap prices over the window ending at the requested time, or the
+// price of the last swap when the window is zero.
+type twapSource struct {
+	chainID string
+	window  time.Duration
+	pools   map[string]uint64
+
+	swapRepo repository.SwapRepository
+	poolRepo repository.PoolRepository
+}
+
+// NewTWAPSource returns the source pricing the denoms of pools, a denom is
+// mapped to the id of its pool with the USD-pegged asset.
+func NewTWAPSource(chainID string, window time.Duration, pools map[string]uint64) Source {
+	return &twapSource{
+		chainID:  chainID,
+		window:   window,
+		pools:    pools,
+		swapRepo: repository.NewSwapRepository(),
+		poolRepo: repository.NewPoolRepository(),
+	}
+}
+
+func (s *twapSource) Name() string {
+	return config.PriceSourceTWAP
+}
+
+// Price returns the average ending at time t, or the price of the last swap
+// up to t, at its time, when the window is zero.
+func (s *twapSource) Price(asset *modelv2.Asset, t time.Time) (Point, error) {
+	points, err := s.points(asset, t.Add(-s.window), t)
+	if err != nil {
+		return Point{}, err
+	}
+
+	price, ok := TimeWeighted(points, t.Add(-s.window), t)
+	if !ok {
+		return Point{}, ErrNoPrice
+	}
+
+	if s.window == 0 {
+		return points[len(points)-1], nil
+	}
+
+	return Point{Time: t, Price: price}, nil
+}
+
+// History returns the prices at the end of every window from time from to
+// time to, or every hour for the spot prices.
+func (s *twapSource) History(asset *modelv2.Asset, from, to time.Time) ([]Point, error) {
+	step := s.window
+	if step == 0 {
+		step = twapSpotStep
+	}
+
+	history := make([]Point, 0)
+	for chunkStart := from; chunkStart.Before(to); chunkStart = chunkStart.Add(twapChunk) {
+		chunkEnd := chunkStart.Add(twapChunk)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+
+		points, err := s.points(asset, chunkStart.Add(-s.window), chunkEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, TimeWeightedSeries(points, chunkStart, chunkEnd, s.window, step)...)
+	}
+
+	return history, nil
+}
+
+// points returns the USD prices of the swaps of the pool of asset from time
+// from to time to, the first point is the last price before from.
+func (s *twapSource) points(asset *modelv2.Asset, from, to time.Time) ([]Point, error) {
+	poolID, ok := s.pools[asset.Denom]
+	if !ok {
+		return nil, ErrNoPrice
+	}
+
+	pool := s.poolRepo.FindByPoolID(poolID)
+	base := pool.GetBaseAsset()
+	quote := pool.GetQuoteAsset()
+	if base == nil || quote == nil {
+		return nil, fmt.Errorf("pool %d is not tracked", poolID)
+	}
+
+	// swap prices are the prices of the base asset in quote asset
+	inverted := false
+	switch asset.Denom {
+	case base.Denom:
+	case quote.Denom:
+		inverted = true
+	default:
+		return nil, fmt.Errorf("pool %d does not contain %s", poolID, asset.Denom)
+	}
+
+	id := int64(poolID)
+	swaps, err := s.swapRepo.FindByTime(s.chainID, &id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, 0, len(swaps)+1)
+	if last := s.swapRepo.FindLastPrice(id, from); last.Price > 0 {
+		swaps = append([]*modelv2.Swap{last}, swaps...)
+	}
+
+	for _, swap := range swaps {
+		if swap.Price <= 0 {
+			continue
+		}
+
+		price := swap.Price
+		if inverted {
+			price = 1 / price
+		}
+		points = append(points, Point{Time: swap.Time, Price: price})
+	}
+
+	return points, nil
+}
+
+// TimeWeighted returns the time-weighted average of the prices from time from
+// to time to, every price holds until the next point. The points must be
+// sorted by time, the last point up to from opens the range. The time before
+// the first price is not counted, when from equals to the price at to is
+// returned.
+func TimeWeighted(points []Point, from, to time.Time) (float64, bool) {
+	// index of the first point after from
+	i := sort.Search(len(points), func(i int) bool {
+		return points[i].Time.After(from)
+	})
+
+	price := float64(0)
+	if i > 0 {
+		price = points[i-1].Price
+	}
+
+	sum := float64(0)
+	weight := float64(0)
+	cursor := from
+
+	for ; i < len(points) && !points[i].Time.After(to); i++ {
+		if price > 0 {
+			d := points[i].Time.Sub(cursor).Seconds()
+			sum += price * d
+			weight += d
+		}
+
+		cursor = points[i].Time
+		price = points[i].Price
+	}
+
+	if price > 0 {
+		d := to.Sub(cursor).Seconds()
+		sum += price * d
+		weight += d
+	}
+
+	if weight == 0 {
+		return price, price > 0
+	}
+
+	return sum / weight, true
+}
+
+// TimeWeightedSeries returns the time-weighted average prices over window at
+// every step after time from up to time to, the steps are aligned to step.
+func TimeWeightedSeries(points []Point, from, to time.Time, window, step time.Duration) []Point {
+	series := make([]Point, 0)
+
+	for end := from.Truncate(step).Add(step); !end.After(to); end = end.Add(step) {
+		if price, ok := TimeWeighted(points, end.Add(-window), end); ok {
+			series = append(series, Point{Time: end, Price: price})
+		}
+	}
+
+	return series
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	w3t "github.com/angelorc/sinfonia-go/server/web3token"
+	"github.com/labstack/echo"
+)
+
+const (
+	HeaderAuthorization      = "Authorization"
+	HeaderPlaygroundPassword = "Playground-Password"
+)
+
+type contextKey string
+
+const (
+	principalKey     contextKey = "principal"
+	introspectionKey contextKey = "introspection"
+)
+
+// Principal is the account authenticated by the web3token of a request.
+type Principal struct {
+	Address    string
+	AddressKey string
+	// PubKey is the base64 secp256k1 public key signing the token
+	PubKey string
+	Domain string
+}
+
+// VerifyFunc verifies the bearer of the Authorization header and returns the
+// authenticated principal.
+type VerifyFunc func(bearer string) (*Principal, error)
+
+// Web3TokenVerifier returns the VerifyFunc of the web3tokens accepted by
+// verifier, the address of the principal is the signer of the token.
+func Web3TokenVerifier(verifier *w3t.Verifier) VerifyFunc {
+	return func(bearer string) (*Principal, error) {
+		token, err := verifier.Verify(bearer)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Principal{
+			Address:    token.Signer,
+			AddressKey: hex.EncodeToString(token.GetAddress()),
+			PubKey:     token.PubKey.Value,
+			Domain:     token.GetDomain(),
+		}, nil
+	}
+}
+
+// CheckOrigin returns the origin check of the websocket upgrades: the
+// browsers must connect from the host of the server or from one of domains,
+// the clients sending no Origin are accepted.
+func CheckOrigin(domains []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" {
+			return false
+		}
+
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+
+		for _, domain := range domains {
+			if strings.EqualFold(u.Host, domain) || strings.EqualFold(u.Hostname(), domain) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// NonceHandler issues the nonces of the web3tokens, at most limit per minute
+// to a client IP.
+func NonceHandler(nonces w3t.NonceStore, ttl time.Duration, limit int) echo.HandlerFunc {
+	limiter := newRateLimiter(limit, time.Minute)
+
+	return func(c echo.Context) error {
+		if !limiter.Allow(c.RealIP(), time.Now()) {
+			return echo.NewHTTPError(http.StatusTooManyRequests, "too many nonces requested, retry later")
+		}
+
+		nonce, expireAt, err := nonces.Issue(ttl)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"nonce":     nonce,
+			"expire_at": expireAt.Unix(),
+		})
+	}
+}
+
+// Middleware verifies the web3token of a request once and stores the verified
+// principal in the context of the request, the requests without a valid
+// token go on without a principal. The playground password enables the
+// introspection of the request.
+func Middleware(verify VerifyFunc, playgroundPass string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			ctx := req.Context()
+
+			if bearer := req.Header.Get(HeaderAuthorization); bearer != "" {
+				if principal, err := verify(bearer); err == nil {
+					ctx = WithPrincipal(ctx, principal)
+				}
+			}
+
+			introspection := req.Header.Get(HeaderPlaygroundPassword) == playgroundPass
+			ctx = context.WithValue(ctx, introspectionKey, introspection)
+
+			c.SetRequest(req.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the principal authenticated by the request of
+// ctx, false when the request has no valid token.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// IntrospectionFromContext tells if the request of ctx sent the playground
+// password.
+func IntrospectionFromContext(ctx context.Context) bool {
+	introspection, _ := ctx.Value(introspectionKey).(bool)
+	return introspection
+}
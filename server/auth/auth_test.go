@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	w3t "github.com/angelorc/sinfonia-go/server/web3token"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/require"
+)
+
+var testVerify = Web3TokenVerifier(&w3t.Verifier{
+	Domains:  []string{"test.com"},
+	Prefixes: []string{"bitsong", "osmo"},
+})
+
+// newBearer returns the bearer of a web3token signed by a new key and the
+// bitsong address of the key.
+func newBearer(t *testing.T, domain string) (string, string) {
+	priv := secp256k1.GenPrivKey()
+
+	signer, err := bech32.ConvertAndEncode("bitsong", priv.PubKey().Address())
+	require.NoError(t, err)
+
+	token := &w3t.Web3Token{
+		Signer:  signer,
+		Payload: w3t.Payload{Domain: domain, ExpireAt: time.Now().Add(5 * time.Minute).Unix()},
+		PubKey: w3t.PubKey{
+			Type:  "tendermint/PubKeySecp256k1",
+			Value: base64.StdEncoding.EncodeToString(priv.PubKey().Bytes()),
+		},
+	}
+
+	sig, err := priv.Sign(token.GetMsg())
+	require.NoError(t, err)
+	token.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	bz, err := json.Marshal(token)
+	require.NoError(t, err)
+
+	return "Bearer " + base64.StdEncoding.EncodeToString(bz), signer
+}
+
+func newTestServer() *httptest.Server {
+	e := echo.New()
+	e.GET("/whoami", func(c echo.Context) error {
+		// let the requests interleave
+		time.Sleep(time.Millisecond)
+
+		principal, ok := PrincipalFromContext(c.Request().Context())
+		if !ok {
+			return c.String(http.StatusOK, "anonymous")
+		}
+
+		return c.String(http.StatusOK, principal.Address)
+	}, Middleware(testVerify, "secret"))
+
+	return httptest.NewServer(e)
+}
+
+func whoami(url, bearer string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url+"/whoami", nil)
+	if err != nil {
+		return "", err
+	}
+	if bearer != "" {
+		req.Header.Set(HeaderAuthorization, bearer)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	bz, err := ioutil.ReadAll(res.Body)
+	return string(bz), err
+}
+
+func TestWeb3TokenVerifier(t *testing.T) {
+	_, err := testVerify("Bearer invalid")
+	require.Error(t, err)
+
+	bearer, address := newBearer(t, "test.com")
+	principal, err := testVerify(bearer)
+	require.NoError(t, err)
+	require.Equal(t, address, principal.Address)
+	require.Equal(t, "test.com", principal.Domain)
+
+	bearer, _ = newBearer(t, "other.com")
+	_, err = testVerify(bearer)
+	require.Error(t, err)
+}
+
+func TestMiddleware_ConcurrentRequests(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	type caller struct {
+		bearer  string
+		address string
+	}
+
+	callers := []caller{{bearer: "", address: "anonymous"}, {bearer: "Bearer invalid", address: "anonymous"}}
+	for i := 0; i < 8; i++ {
+		bearer, address := newBearer(t, "test.com")
+		callers = append(callers, caller{bearer: bearer, address: address})
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(callers)*20)
+
+	for round := 0; round < 20; round++ {
+		for _, c := range callers {
+			wg.Add(1)
+			go func(c caller) {
+				defer wg.Done()
+
+				got, err := whoami(srv.URL, c.bearer)
+				if err != nil {
+					errs <- err
+				} else if got != c.address {
+					errs <- fmt.Errorf("got principal %s, want %s", got, c.address)
+				}
+			}(c)
+		}
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestMiddleware_Introspection(t *testing.T) {
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, fmt.Sprint(IntrospectionFromContext(c.Request().Context())))
+	}, Middleware(testVerify, "secret"))
+
+	for password, want := range map[string]string{"secret": "true", "wrong": "false", "": "false"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if password != "" {
+			req.Header.Set(HeaderPlaygroundPassword, password)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		require.Equal(t, want, rec.Body.String(), "password %q", password)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	check := CheckOrigin([]string{"sinfonia.zone", "localhost:3000"})
+
+	for origin, want := range map[string]bool{
+		"":                        true,
+		"https://sinfonia.zone":   true,
+		"https://SINFONIA.zone":   true,
+		"http://localhost:3000":   true,
+		"http://localhost:8080":   false,
+		"https://api.example.com": true,
+		"https://evil.com":        false,
+		"null":                    false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "https://api.example.com/query", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+
+		require.Equal(t, want, check(req), "origin %q", origin)
+	}
+}
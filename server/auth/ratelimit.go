@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter allows limit requests per key in every window, the counts are
+// dropped when a window ends so that the memory is bounded by the keys of a
+// single window.
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	start  time.Time
+	counts map[string]int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, counts: make(map[string]int)}
+}
+
+// Allow counts a request of key at now, it reports whether the request is
+// within the limit.
+func (l *rateLimiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+
+	if l.counts[key] >= l.limit {
+		return false
+	}
+	l.counts[key]++
+
+	return true
+}
@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(2, time.Minute)
+	now := time.Now()
+
+	require.True(t, limiter.Allow("1.1.1.1", now))
+	require.True(t, limiter.Allow("1.1.1.1", now))
+	require.False(t, limiter.Allow("1.1.1.1", now))
+
+	// the keys are limited separately
+	require.True(t, limiter.Allow("2.2.2.2", now))
+
+	// a new window
+	require.True(t, limiter.Allow("1.1.1.1", now.Add(time.Minute)))
+}
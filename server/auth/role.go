@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+// Role is the permission of a principal on a resource, a role includes the
+// permissions of the lower ones.
+type Role string
+
+const (
+	// RoleViewer is the role of any authenticated principal.
+	RoleViewer Role = "viewer"
+	// RoleOwner is the role of the owners of a resource.
+	RoleOwner Role = "owner"
+	// RoleAdmin is the role of the configured admins, on every resource.
+	RoleAdmin Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleViewer: 1,
+	RoleOwner:  2,
+	RoleAdmin:  3,
+}
+
+// Includes tells if r has the permissions of role.
+func (r Role) Includes(role Role) bool {
+	return roleLevels[r] > 0 && roleLevels[r] >= roleLevels[role]
+}
+
+var (
+	ErrUnauthenticated = errors.New("access denied")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+// Roles assigns the roles of the principals. The addresses are compared by
+// their key, the same account is an admin or an owner with the address of
+// any chain prefix.
+type Roles struct {
+	admins map[string]struct{}
+}
+
+// NewRoles returns the roles of the bech32 admin addresses.
+func NewRoles(admins []string) (*Roles, error) {
+	r := &Roles{admins: make(map[string]struct{}, len(admins))}
+
+	for _, admin := range admins {
+		key, err := utility.AddressKey(admin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin: %w", err)
+		}
+		r.admins[key] = struct{}{}
+	}
+
+	return r, nil
+}
+
+// IsAdmin tells if principal is a configured admin.
+func (r *Roles) IsAdmin(principal *Principal) bool {
+	_, ok := r.admins[principal.AddressKey]
+	return ok
+}
+
+// Role returns the role of principal on a resource owned by owners, the
+// invalid owner addresses own nothing.
+func (r *Roles) Role(principal *Principal, owners ...string) Role {
+	if r.IsAdmin(principal) {
+		return RoleAdmin
+	}
+
+	for _, owner := range owners {
+		if key, err := utility.AddressKey(owner); err == nil && key == principal.AddressKey {
+			return RoleOwner
+		}
+	}
+
+	return RoleViewer
+}
+
+// Authorize returns the principal of ctx and its role on a resource owned by
+// owners, ErrForbidden when the role does not include role.
+func (r *Roles) Authorize(ctx context.Context, role Role, owners ...string) (*Principal, Role, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil, "", ErrUnauthenticated
+	}
+
+	got := r.Role(principal, owners...)
+	if !got.Includes(role) {
+		return principal, got, ErrForbidden
+	}
+
+	return principal, got, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrincipal returns the principal of a new key and its address with each
+// prefix.
+func newPrincipal(t *testing.T, prefixes ...string) (*Principal, []string) {
+	addr := secp256k1.GenPrivKey().PubKey().Address()
+
+	addresses := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		address, err := bech32.ConvertAndEncode(prefix, addr)
+		require.NoError(t, err)
+		addresses[i] = address
+	}
+
+	return &Principal{Address: addresses[0], AddressKey: hex.EncodeToString(addr)}, addresses
+}
+
+func TestRole_Includes(t *testing.T) {
+	require.True(t, RoleAdmin.Includes(RoleOwner))
+	require.True(t, RoleAdmin.Includes(RoleViewer))
+	require.True(t, RoleOwner.Includes(RoleOwner))
+	require.False(t, RoleOwner.Includes(RoleAdmin))
+	require.False(t, RoleViewer.Includes(RoleOwner))
+	require.False(t, Role("unknown").Includes(RoleViewer))
+}
+
+func TestRoles(t *testing.T) {
+	admin, adminAddrs := newPrincipal(t, "bitsong", "osmo")
+	owner, ownerAddrs := newPrincipal(t, "bitsong", "osmo")
+	viewer, _ := newPrincipal(t, "bitsong")
+
+	_, err := NewRoles([]string{"bitsong1invalid"})
+	require.Error(t, err)
+
+	// the admin is configured with its osmosis address
+	roles, err := NewRoles([]string{adminAddrs[1]})
+	require.NoError(t, err)
+
+	require.Equal(t, RoleAdmin, roles.Role(admin, ownerAddrs[0]))
+	require.Equal(t, RoleOwner, roles.Role(owner, ownerAddrs[0]))
+	require.Equal(t, RoleOwner, roles.Role(owner, "invalid", ownerAddrs[1]))
+	require.Equal(t, RoleViewer, roles.Role(viewer, ownerAddrs[0]))
+	require.Equal(t, RoleViewer, roles.Role(owner))
+
+	_, _, err = roles.Authorize(context.Background(), RoleViewer)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+
+	_, role, err := roles.Authorize(WithPrincipal(context.Background(), viewer), RoleOwner, ownerAddrs[0])
+	require.ErrorIs(t, err, ErrForbidden)
+	require.Equal(t, RoleViewer, role)
+
+	principal, role, err := roles.Authorize(WithPrincipal(context.Background(), owner), RoleOwner, ownerAddrs[0])
+	require.NoError(t, err)
+	require.Equal(t, RoleOwner, role)
+	require.Equal(t, owner, principal)
+}
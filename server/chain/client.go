@@ -0,0 +1,43 @@
+package chain
+
+import (
+	"context"
+	"crypto/tls"
+	"regexp"
+
+	"github.com/angelorc/sinfonia-go/config"
+	merkledroptypes "github.com/bitsongofficial/go-bitsong/x/merkledrop/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Client queries the state of the bitsong chain needed by the server, the
+// connection is opened lazily by the first query.
+type Client struct {
+	config *config.ChainConfig
+	grpc   *grpc.ClientConn
+}
+
+func NewClient(config *config.ChainConfig) (*Client, error) {
+	var grpcOpts []grpc.DialOption
+	if config.GRPCInsecure {
+		grpcOpts = append(grpcOpts, grpc.WithInsecure())
+	} else {
+		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	}
+
+	address := regexp.MustCompile("https?://").ReplaceAllString(config.GRPCAddr, "")
+	grpcConn, err := grpc.Dial(address, grpcOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		config: config,
+		grpc:   grpcConn,
+	}, nil
+}
+
+func (c *Client) QueryMerkledropByID(ctx context.Context, mdID uint64) (*merkledroptypes.QueryMerkledropResponse, error) {
+	return merkledroptypes.NewQueryClient(c.grpc).Merkledrop(ctx, &merkledroptypes.QueryMerkledropRequest{Id: mdID})
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	"github.com/angelorc/sinfonia-go/utility"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const msgTypeIssueFantoken = "/bitsong.fantoken.MsgIssue"
+
+// chains returns the indexed chains.
+func (r *Resolver) chains() []config.ChainConfig {
+	return []config.ChainConfig{r.Config.Bitsong, r.Config.Osmosis}
+}
+
+// accountAddresses returns the addresses of the key of address on the indexed
+// chains, only address is returned when it is not a valid bech32 address.
+func (r *Resolver) accountAddresses(address string) []*modelv2.AccountAddress {
+	key, err := utility.AddressKey(address)
+	if err != nil {
+		return []*modelv2.AccountAddress{{Address: address}}
+	}
+
+	addresses := make([]*modelv2.AccountAddress, 0)
+	for _, chain := range r.chains() {
+		chainAddress, err := utility.EncodeAddressKey(key, chain.AccountPrefix)
+		if err != nil {
+			return []*modelv2.AccountAddress{{Address: address}}
+		}
+
+		addresses = append(addresses, &modelv2.AccountAddress{ChainID: chain.ChainID, Address: chainAddress})
+	}
+
+	return addresses
+}
+
+// addressKey converts the address filter to the key of the address, so that
+// the address of the key on any chain matches. A key is kept as it is.
+func addressKey(filter *string) *string {
+	if filter == nil {
+		return nil
+	}
+
+	key, err := utility.AddressKey(*filter)
+	if err != nil {
+		return filter
+	}
+
+	return &key
+}
+
+// chainAddress returns the address of the key of address on chain.
+func chainAddress(addresses []*modelv2.AccountAddress, chain config.ChainConfig) string {
+	for _, address := range addresses {
+		if address.ChainID == chain.ChainID {
+			return address.Address
+		}
+	}
+
+	return addresses[0].Address
+}
+
+func addressList(addresses []*modelv2.AccountAddress) []string {
+	list := make([]string, len(addresses))
+	for i, address := range addresses {
+		list[i] = address.Address
+	}
+
+	return list
+}
+
+// claimedProofs is the filter of the merkledrop proofs claimed by addresses.
+func claimedProofs(addresses []string) *model.MerkledropProofWhere {
+	claimed := true
+
+	return &model.MerkledropProofWhere{
+		Claimed: &claimed,
+		OR:      []bson.M{{"address": bson.M{"$in": addresses}}},
+	}
+}
+
+// liquidityPositions replays the joins and the exits of sender into its
+// positions, by pool.
+func liquidityPositions(sender string, poolID *uint64) ([]*modelv2.LiquidityPosition, error) {
+	filter := &modelv2.LiquidityEventFilter{Sender: &sender, PoolID: poolID}
+
+	orderBy := "height_ASC"
+	events, err := repository.NewLiquidityRepository().Find(filter, &types.PaginationReq{OrderBy: &orderBy})
+	if err != nil {
+		return nil, err
+	}
+
+	poolRepo := repository.NewPoolRepository()
+	pools := make(map[uint64]*modelv2.Pool)
+	positions := make([]*modelv2.LiquidityPosition, 0)
+	byPool := make(map[uint64]*modelv2.LiquidityPosition)
+
+	for _, evt := range events {
+		position, ok := byPool[evt.PoolID]
+		if !ok {
+			pools[evt.PoolID] = poolRepo.FindByPoolID(evt.PoolID)
+			position = modelv2.NewLiquidityPosition(sender, evt.PoolID)
+			byPool[evt.PoolID] = position
+			positions = append(positions, position)
+		}
+
+		position.Apply(evt, pools[evt.PoolID].Weights())
+	}
+
+	// the share is computed from the total shares of the last liquidity
+	// snapshot of the pool
+	historicalLiqRepo := repository.NewHistoricalLiquidityRepository()
+	for _, position := range positions {
+		pool := pools[position.PoolID]
+
+		latest := historicalLiqRepo.Latest(pool.ChainID, pool.PoolID)
+		if !latest.ID.IsZero() {
+			position.Estimate(latest.Shares(), latest.UsdValue)
+		}
+	}
+
+	return positions, nil
+}
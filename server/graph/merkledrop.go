@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/angelorc/sinfonia-go/server/auth"
+	model1 "github.com/angelorc/sinfonia-go/server/graph/model"
+	"github.com/angelorc/sinfonia-go/utility"
+	merkledroptypes "github.com/bitsongofficial/go-bitsong/x/merkledrop/types"
+)
+
+// maxReportErrors is the max number of entry errors in a proofs report.
+const maxReportErrors = 100
+
+// MerkledropQuerier queries the merkledrops of the bitsong chain.
+type MerkledropQuerier interface {
+	QueryMerkledropByID(ctx context.Context, mdID uint64) (*merkledroptypes.QueryMerkledropResponse, error)
+}
+
+func (r *Resolver) queryMerkledrop(ctx context.Context, mdID uint64) (*merkledroptypes.Merkledrop, error) {
+	res, err := r.Bitsong.QueryMerkledropByID(ctx, mdID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query merkledrop %d: %w", mdID, err)
+	}
+
+	return &res.Merkledrop, nil
+}
+
+// authorizeMerkledrop returns the principal of ctx when it is the on-chain
+// owner of the merkledrop or an admin, with the queried merkledrop. The owner
+// is not queried for the admins and the merkledrop is nil.
+func (r *Resolver) authorizeMerkledrop(ctx context.Context, mdID uint64) (*auth.Principal, auth.Role, *merkledroptypes.Merkledrop, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, "", nil, auth.ErrUnauthenticated
+	}
+
+	if r.Roles.IsAdmin(principal) {
+		return principal, auth.RoleAdmin, nil, nil
+	}
+
+	md, err := r.queryMerkledrop(ctx, mdID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	principal, role, err := r.Roles.Authorize(ctx, auth.RoleOwner, md.Owner)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return principal, role, md, nil
+}
+
+// verifyMerkledropProofs verifies the entries of list against the merkle root
+// of md and the sum of their amounts against the amount of md. The addresses
+// of the entries are bech32 addresses with prefix.
+func verifyMerkledropProofs(md *merkledroptypes.Merkledrop, prefix string, list List) (*model1.MerkledropProofsReport, error) {
+	root, err := hex.DecodeString(md.MerkleRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merkle root %s of merkledrop %d", md.MerkleRoot, md.Id)
+	}
+
+	report := &model1.MerkledropProofsReport{
+		MerkleRoot:    md.MerkleRoot,
+		Entries:       len(list),
+		ExpectedTotal: md.Amount.String(),
+		Errors:        make([]*model1.MerkledropProofError, 0),
+	}
+
+	addresses := make([]string, 0, len(list))
+	for address := range list {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		if list[addresses[i]].Index != list[addresses[j]].Index {
+			return list[addresses[i]].Index < list[addresses[j]].Index
+		}
+		return addresses[i] < addresses[j]
+	})
+
+	total := new(big.Int)
+	indexes := make(map[int64]string, len(list))
+
+	for _, address := range addresses {
+		item := list[address]
+
+		amount, err := verifyMerkledropEntry(address, item, prefix, root)
+		if amount != nil {
+			total.Add(total, amount)
+		}
+		if err == nil {
+			if other, ok := indexes[item.Index]; ok {
+				err = fmt.Errorf("index already used by %s", other)
+			}
+			indexes[item.Index] = address
+		}
+
+		if err != nil {
+			if len(report.Errors) < maxReportErrors {
+				report.Errors = append(report.Errors, &model1.MerkledropProofError{
+					Index:   int(item.Index),
+					Address: address,
+					Error:   err.Error(),
+				})
+			}
+			continue
+		}
+
+		report.ValidEntries++
+	}
+
+	report.Total = total.String()
+	report.Valid = report.ValidEntries == report.Entries && total.Cmp(md.Amount.BigInt()) == 0
+
+	return report, nil
+}
+
+// verifyMerkledropEntry verifies the proof of an entry and returns its
+// amount, the amount is returned for the valid amounts of the invalid
+// entries too.
+func verifyMerkledropEntry(address string, item ListItem, prefix string, root []byte) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(item.Amount, 10)
+	if !ok || amount.Sign() <= 0 || !amount.IsInt64() {
+		return nil, fmt.Errorf("invalid amount %s", item.Amount)
+	}
+
+	if _, err := utility.AddressKey(address); err != nil {
+		return amount, err
+	}
+	if !strings.HasPrefix(address, prefix+"1") {
+		return amount, fmt.Errorf("address without the %s prefix", prefix)
+	}
+
+	if item.Index < 0 {
+		return amount, fmt.Errorf("invalid index %d", item.Index)
+	}
+
+	proof := make([][]byte, len(item.Proof))
+	for i, p := range item.Proof {
+		bz, err := hex.DecodeString(p)
+		if err != nil {
+			return amount, fmt.Errorf("invalid proof %s", p)
+		}
+		proof[i] = bz
+	}
+
+	leaf := utility.MerkledropLeaf(uint64(item.Index), address, amount.String())
+	if !utility.VerifyMerkledropProof(leaf, proof, root) {
+		return amount, fmt.Errorf("proof not matching the merkle root")
+	}
+
+	return amount, nil
+}
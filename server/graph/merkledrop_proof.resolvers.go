@@ -5,7 +5,6 @@ package graph
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/angelorc/sinfonia-go/mongo/model"
 	"github.com/angelorc/sinfonia-go/server/graph/generated"
@@ -25,19 +24,9 @@ func (r *merkledropProofResolver) Merkledrop(ctx context.Context, obj *model.Mer
 	return &item, nil
 }
 
-func (r *merkledropProofWhereResolver) Claimed(ctx context.Context, obj *model.MerkledropProofWhere, data *bool) error {
-	panic(fmt.Errorf("not implemented"))
-}
-
 // MerkledropProof returns generated.MerkledropProofResolver implementation.
 func (r *Resolver) MerkledropProof() generated.MerkledropProofResolver {
 	return &merkledropProofResolver{r}
 }
 
-// MerkledropProofWhere returns generated.MerkledropProofWhereResolver implementation.
-func (r *Resolver) MerkledropProofWhere() generated.MerkledropProofWhereResolver {
-	return &merkledropProofWhereResolver{r}
-}
-
 type merkledropProofResolver struct{ *Resolver }
-type merkledropProofWhereResolver struct{ *Resolver }
@@ -0,0 +1,152 @@
+package graph
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/angelorc/sinfonia-go/server/auth"
+	"github.com/angelorc/sinfonia-go/utility"
+	merkledroptypes "github.com/bitsongofficial/go-bitsong/x/merkledrop/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+type testMerkledrops map[uint64]string
+
+func (m testMerkledrops) QueryMerkledropByID(ctx context.Context, mdID uint64) (*merkledroptypes.QueryMerkledropResponse, error) {
+	owner, ok := m[mdID]
+	if !ok {
+		return nil, errors.New("merkledrop not found")
+	}
+
+	return &merkledroptypes.QueryMerkledropResponse{
+		Merkledrop: merkledroptypes.Merkledrop{Id: mdID, Owner: owner},
+	}, nil
+}
+
+func newPrincipal(t *testing.T) (*auth.Principal, string) {
+	addr := secp256k1.GenPrivKey().PubKey().Address()
+	address, err := bech32.ConvertAndEncode("bitsong", addr)
+	require.NoError(t, err)
+
+	return &auth.Principal{Address: address, AddressKey: hex.EncodeToString(addr)}, address
+}
+
+func TestAuthorizeMerkledrop(t *testing.T) {
+	admin, adminAddr := newPrincipal(t)
+	owner, ownerAddr := newPrincipal(t)
+	viewer, _ := newPrincipal(t)
+
+	roles, err := auth.NewRoles([]string{adminAddr})
+	require.NoError(t, err)
+
+	r := &Resolver{Roles: roles, Bitsong: testMerkledrops{1: ownerAddr}}
+
+	_, _, _, err = r.authorizeMerkledrop(context.Background(), 1)
+	require.ErrorIs(t, err, auth.ErrUnauthenticated)
+
+	_, role, md, err := r.authorizeMerkledrop(auth.WithPrincipal(context.Background(), owner), 1)
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleOwner, role)
+	require.Equal(t, ownerAddr, md.Owner)
+
+	_, _, _, err = r.authorizeMerkledrop(auth.WithPrincipal(context.Background(), viewer), 1)
+	require.ErrorIs(t, err, auth.ErrForbidden)
+
+	_, _, _, err = r.authorizeMerkledrop(auth.WithPrincipal(context.Background(), owner), 2)
+	require.Error(t, err, "unknown merkledrop")
+
+	// the admins are not checked against the chain
+	_, role, md, err = r.authorizeMerkledrop(auth.WithPrincipal(context.Background(), admin), 2)
+	require.NoError(t, err)
+	require.Equal(t, auth.RoleAdmin, role)
+	require.Nil(t, md)
+}
+
+// newTestList returns a list of 4 entries of 100 and its merkle root.
+func newTestList(t *testing.T) (List, string) {
+	list := make(List)
+	leaves := make([][]byte, 4)
+
+	for i := range leaves {
+		_, address := newPrincipal(t)
+		list[address] = ListItem{Index: int64(i), Amount: "100"}
+		leaves[i] = utility.MerkledropLeaf(uint64(i), address, "100")
+	}
+
+	left := utility.MerkledropHashPair(leaves[0], leaves[1])
+	right := utility.MerkledropHashPair(leaves[2], leaves[3])
+	for address, item := range list {
+		var proof [][]byte
+		switch item.Index {
+		case 0:
+			proof = [][]byte{leaves[1], right}
+		case 1:
+			proof = [][]byte{leaves[0], right}
+		case 2:
+			proof = [][]byte{leaves[3], left}
+		case 3:
+			proof = [][]byte{leaves[2], left}
+		}
+
+		for _, p := range proof {
+			item.Proof = append(item.Proof, hex.EncodeToString(p))
+		}
+		list[address] = item
+	}
+
+	return list, hex.EncodeToString(utility.MerkledropHashPair(left, right))
+}
+
+func TestVerifyMerkledropProofs(t *testing.T) {
+	list, root := newTestList(t)
+	md := &merkledroptypes.Merkledrop{Id: 1, MerkleRoot: root, Amount: sdk.NewInt(400)}
+
+	report, err := verifyMerkledropProofs(md, "bitsong", list)
+	require.NoError(t, err)
+	require.True(t, report.Valid)
+	require.Equal(t, 4, report.ValidEntries)
+	require.Equal(t, "400", report.Total)
+	require.Empty(t, report.Errors)
+
+	// the total differs from the merkledrop amount
+	md.Amount = sdk.NewInt(500)
+	report, err = verifyMerkledropProofs(md, "bitsong", list)
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Equal(t, 4, report.ValidEntries)
+	require.Equal(t, "500", report.ExpectedTotal)
+	md.Amount = sdk.NewInt(400)
+
+	var tampered string
+	for address, item := range list {
+		if item.Index == 2 {
+			tampered = address
+			item.Amount = "200"
+			list[address] = item
+		}
+	}
+
+	report, err = verifyMerkledropProofs(md, "bitsong", list)
+	require.NoError(t, err)
+	require.False(t, report.Valid)
+	require.Equal(t, 3, report.ValidEntries)
+	require.Equal(t, "500", report.Total)
+	require.Len(t, report.Errors, 1)
+	require.Equal(t, tampered, report.Errors[0].Address)
+	require.Equal(t, 2, report.Errors[0].Index)
+
+	// the entries are bitsong addresses
+	report, err = verifyMerkledropProofs(md, "osmo", list)
+	require.NoError(t, err)
+	require.Equal(t, 0, report.ValidEntries)
+	require.Len(t, report.Errors, 4)
+
+	md.MerkleRoot = "not hex"
+	_, err = verifyMerkledropProofs(md, "bitsong", list)
+	require.Error(t, err)
+}
@@ -0,0 +1,586 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+)
+
+type AccountConnection struct {
+	Edges    []*AccountEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type AccountEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *modelv2.Account `json:"node"`
+}
+
+type FantokenConnection struct {
+	Edges    []*FantokenEdge `json:"edges"`
+	PageInfo *PageInfo       `json:"pageInfo"`
+}
+
+type FantokenEdge struct {
+	Cursor string            `json:"cursor"`
+	Node   *modelv2.Fantoken `json:"node"`
+}
+
+type IncentiveConnection struct {
+	Edges    []*IncentiveEdge `json:"edges"`
+	PageInfo *PageInfo        `json:"pageInfo"`
+}
+
+type IncentiveEdge struct {
+	Cursor string             `json:"cursor"`
+	Node   *modelv2.Incentive `json:"node"`
+}
+
+type LiquidityEventConnection struct {
+	Edges    []*LiquidityEventEdge `json:"edges"`
+	PageInfo *PageInfo             `json:"pageInfo"`
+}
+
+type LiquidityEventEdge struct {
+	Cursor string                  `json:"cursor"`
+	Node   *modelv2.LiquidityEvent `json:"node"`
+}
+
+type MerkledropConnection struct {
+	Edges    []*MerkledropEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type MerkledropEdge struct {
+	Cursor string            `json:"cursor"`
+	Node   *model.Merkledrop `json:"node"`
+}
+
+type MerkledropProofConnection struct {
+	Edges    []*MerkledropProofEdge `json:"edges"`
+	PageInfo *PageInfo              `json:"pageInfo"`
+}
+
+type MerkledropProofEdge struct {
+	Cursor string                 `json:"cursor"`
+	Node   *model.MerkledropProof `json:"node"`
+}
+
+type MerkledropProofError struct {
+	Index   int    `json:"index"`
+	Address string `json:"address"`
+	Error   string `json:"error"`
+}
+
+type MerkledropProofsReport struct {
+	Valid         bool                    `json:"valid"`
+	MerkleRoot    string                  `json:"merkle_root"`
+	Entries       int                     `json:"entries"`
+	ValidEntries  int                     `json:"valid_entries"`
+	Total         string                  `json:"total"`
+	ExpectedTotal string                  `json:"expected_total"`
+	Errors        []*MerkledropProofError `json:"errors"`
+}
+
+type MerkledropUpdateResult struct {
+	Merkledrop *model.Merkledrop       `json:"merkledrop"`
+	Report     *MerkledropProofsReport `json:"report"`
+}
+
+type MessageConnection struct {
+	Edges    []*MessageEdge `json:"edges"`
+	PageInfo *PageInfo      `json:"pageInfo"`
+}
+
+type MessageEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *modelv2.Message `json:"node"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+type PoolConnection struct {
+	Edges    []*PoolEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type PoolEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *modelv2.Pool `json:"node"`
+}
+
+type SwapConnection struct {
+	Edges    []*SwapEdge `json:"edges"`
+	PageInfo *PageInfo   `json:"pageInfo"`
+}
+
+type SwapEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *modelv2.Swap `json:"node"`
+}
+
+type TransactionConnection struct {
+	Edges    []*TransactionEdge `json:"edges"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type TransactionEdge struct {
+	Cursor string               `json:"cursor"`
+	Node   *modelv2.Transaction `json:"node"`
+}
+
+type AccountOrderByEnum string
+
+const (
+	AccountOrderByEnumFirstSeenAsc  AccountOrderByEnum = "first_seen_ASC"
+	AccountOrderByEnumFirstSeenDesc AccountOrderByEnum = "first_seen_DESC"
+)
+
+var AllAccountOrderByEnum = []AccountOrderByEnum{
+	AccountOrderByEnumFirstSeenAsc,
+	AccountOrderByEnumFirstSeenDesc,
+}
+
+func (e AccountOrderByEnum) IsValid() bool {
+	switch e {
+	case AccountOrderByEnumFirstSeenAsc, AccountOrderByEnumFirstSeenDesc:
+		return true
+	}
+	return false
+}
+
+func (e AccountOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *AccountOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e AccountOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type FantokenOrderByEnum string
+
+const (
+	FantokenOrderByEnumIssuedAtAsc  FantokenOrderByEnum = "issued_at_ASC"
+	FantokenOrderByEnumIssuedAtDesc FantokenOrderByEnum = "issued_at_DESC"
+	FantokenOrderByEnumHeightAsc    FantokenOrderByEnum = "height_ASC"
+	FantokenOrderByEnumHeightDesc   FantokenOrderByEnum = "height_DESC"
+)
+
+var AllFantokenOrderByEnum = []FantokenOrderByEnum{
+	FantokenOrderByEnumIssuedAtAsc,
+	FantokenOrderByEnumIssuedAtDesc,
+	FantokenOrderByEnumHeightAsc,
+	FantokenOrderByEnumHeightDesc,
+}
+
+func (e FantokenOrderByEnum) IsValid() bool {
+	switch e {
+	case FantokenOrderByEnumIssuedAtAsc, FantokenOrderByEnumIssuedAtDesc, FantokenOrderByEnumHeightAsc, FantokenOrderByEnumHeightDesc:
+		return true
+	}
+	return false
+}
+
+func (e FantokenOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *FantokenOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = FantokenOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FantokenOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e FantokenOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type IncentiveOrderByEnum string
+
+const (
+	IncentiveOrderByEnumTimeAsc    IncentiveOrderByEnum = "time_ASC"
+	IncentiveOrderByEnumTimeDesc   IncentiveOrderByEnum = "time_DESC"
+	IncentiveOrderByEnumHeightAsc  IncentiveOrderByEnum = "height_ASC"
+	IncentiveOrderByEnumHeightDesc IncentiveOrderByEnum = "height_DESC"
+)
+
+var AllIncentiveOrderByEnum = []IncentiveOrderByEnum{
+	IncentiveOrderByEnumTimeAsc,
+	IncentiveOrderByEnumTimeDesc,
+	IncentiveOrderByEnumHeightAsc,
+	IncentiveOrderByEnumHeightDesc,
+}
+
+func (e IncentiveOrderByEnum) IsValid() bool {
+	switch e {
+	case IncentiveOrderByEnumTimeAsc, IncentiveOrderByEnumTimeDesc, IncentiveOrderByEnumHeightAsc, IncentiveOrderByEnumHeightDesc:
+		return true
+	}
+	return false
+}
+
+func (e IncentiveOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *IncentiveOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IncentiveOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IncentiveOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e IncentiveOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type LiquidityEventOrderByEnum string
+
+const (
+	LiquidityEventOrderByEnumTimeAsc    LiquidityEventOrderByEnum = "time_ASC"
+	LiquidityEventOrderByEnumTimeDesc   LiquidityEventOrderByEnum = "time_DESC"
+	LiquidityEventOrderByEnumHeightAsc  LiquidityEventOrderByEnum = "height_ASC"
+	LiquidityEventOrderByEnumHeightDesc LiquidityEventOrderByEnum = "height_DESC"
+)
+
+var AllLiquidityEventOrderByEnum = []LiquidityEventOrderByEnum{
+	LiquidityEventOrderByEnumTimeAsc,
+	LiquidityEventOrderByEnumTimeDesc,
+	LiquidityEventOrderByEnumHeightAsc,
+	LiquidityEventOrderByEnumHeightDesc,
+}
+
+func (e LiquidityEventOrderByEnum) IsValid() bool {
+	switch e {
+	case LiquidityEventOrderByEnumTimeAsc, LiquidityEventOrderByEnumTimeDesc, LiquidityEventOrderByEnumHeightAsc, LiquidityEventOrderByEnumHeightDesc:
+		return true
+	}
+	return false
+}
+
+func (e LiquidityEventOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *LiquidityEventOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LiquidityEventOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LiquidityEventOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e LiquidityEventOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MerkledropOrderByEnum string
+
+const (
+	MerkledropOrderByEnumTimeAsc    MerkledropOrderByEnum = "time_ASC"
+	MerkledropOrderByEnumTimeDesc   MerkledropOrderByEnum = "time_DESC"
+	MerkledropOrderByEnumHeightAsc  MerkledropOrderByEnum = "height_ASC"
+	MerkledropOrderByEnumHeightDesc MerkledropOrderByEnum = "height_DESC"
+)
+
+var AllMerkledropOrderByEnum = []MerkledropOrderByEnum{
+	MerkledropOrderByEnumTimeAsc,
+	MerkledropOrderByEnumTimeDesc,
+	MerkledropOrderByEnumHeightAsc,
+	MerkledropOrderByEnumHeightDesc,
+}
+
+func (e MerkledropOrderByEnum) IsValid() bool {
+	switch e {
+	case MerkledropOrderByEnumTimeAsc, MerkledropOrderByEnumTimeDesc, MerkledropOrderByEnumHeightAsc, MerkledropOrderByEnumHeightDesc:
+		return true
+	}
+	return false
+}
+
+func (e MerkledropOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *MerkledropOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MerkledropOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MerkledropOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e MerkledropOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MerkledropProofOrderByEnum string
+
+const (
+	MerkledropProofOrderByEnumCreatedAtAsc  MerkledropProofOrderByEnum = "created_at_ASC"
+	MerkledropProofOrderByEnumCreatedAtDesc MerkledropProofOrderByEnum = "created_at_DESC"
+)
+
+var AllMerkledropProofOrderByEnum = []MerkledropProofOrderByEnum{
+	MerkledropProofOrderByEnumCreatedAtAsc,
+	MerkledropProofOrderByEnumCreatedAtDesc,
+}
+
+func (e MerkledropProofOrderByEnum) IsValid() bool {
+	switch e {
+	case MerkledropProofOrderByEnumCreatedAtAsc, MerkledropProofOrderByEnumCreatedAtDesc:
+		return true
+	}
+	return false
+}
+
+func (e MerkledropProofOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *MerkledropProofOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MerkledropProofOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MerkledropProofOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e MerkledropProofOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type MessageOrderByEnum string
+
+const (
+	MessageOrderByEnumHeightAsc  MessageOrderByEnum = "height_ASC"
+	MessageOrderByEnumHeightDesc MessageOrderByEnum = "height_DESC"
+	MessageOrderByEnumTimeAsc    MessageOrderByEnum = "time_ASC"
+	MessageOrderByEnumTimeDesc   MessageOrderByEnum = "time_DESC"
+)
+
+var AllMessageOrderByEnum = []MessageOrderByEnum{
+	MessageOrderByEnumHeightAsc,
+	MessageOrderByEnumHeightDesc,
+	MessageOrderByEnumTimeAsc,
+	MessageOrderByEnumTimeDesc,
+}
+
+func (e MessageOrderByEnum) IsValid() bool {
+	switch e {
+	case MessageOrderByEnumHeightAsc, MessageOrderByEnumHeightDesc, MessageOrderByEnumTimeAsc, MessageOrderByEnumTimeDesc:
+		return true
+	}
+	return false
+}
+
+func (e MessageOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *MessageOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MessageOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MessageOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e MessageOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PoolOrderByEnum string
+
+const (
+	PoolOrderByEnumTimeAsc    PoolOrderByEnum = "time_ASC"
+	PoolOrderByEnumTimeDesc   PoolOrderByEnum = "time_DESC"
+	PoolOrderByEnumHeightAsc  PoolOrderByEnum = "height_ASC"
+	PoolOrderByEnumHeightDesc PoolOrderByEnum = "height_DESC"
+	PoolOrderByEnumPoolIDAsc  PoolOrderByEnum = "pool_id_ASC"
+	PoolOrderByEnumPoolIDDesc PoolOrderByEnum = "pool_id_DESC"
+)
+
+var AllPoolOrderByEnum = []PoolOrderByEnum{
+	PoolOrderByEnumTimeAsc,
+	PoolOrderByEnumTimeDesc,
+	PoolOrderByEnumHeightAsc,
+	PoolOrderByEnumHeightDesc,
+	PoolOrderByEnumPoolIDAsc,
+	PoolOrderByEnumPoolIDDesc,
+}
+
+func (e PoolOrderByEnum) IsValid() bool {
+	switch e {
+	case PoolOrderByEnumTimeAsc, PoolOrderByEnumTimeDesc, PoolOrderByEnumHeightAsc, PoolOrderByEnumHeightDesc, PoolOrderByEnumPoolIDAsc, PoolOrderByEnumPoolIDDesc:
+		return true
+	}
+	return false
+}
+
+func (e PoolOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *PoolOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PoolOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PoolOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e PoolOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SwapOrderByEnum string
+
+const (
+	SwapOrderByEnumTimeAsc    SwapOrderByEnum = "time_ASC"
+	SwapOrderByEnumTimeDesc   SwapOrderByEnum = "time_DESC"
+	SwapOrderByEnumHeightAsc  SwapOrderByEnum = "height_ASC"
+	SwapOrderByEnumHeightDesc SwapOrderByEnum = "height_DESC"
+)
+
+var AllSwapOrderByEnum = []SwapOrderByEnum{
+	SwapOrderByEnumTimeAsc,
+	SwapOrderByEnumTimeDesc,
+	SwapOrderByEnumHeightAsc,
+	SwapOrderByEnumHeightDesc,
+}
+
+func (e SwapOrderByEnum) IsValid() bool {
+	switch e {
+	case SwapOrderByEnumTimeAsc, SwapOrderByEnumTimeDesc, SwapOrderByEnumHeightAsc, SwapOrderByEnumHeightDesc:
+		return true
+	}
+	return false
+}
+
+func (e SwapOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *SwapOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SwapOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SwapOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e SwapOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TransactionOrderByEnum string
+
+const (
+	TransactionOrderByEnumHeightAsc  TransactionOrderByEnum = "height_ASC"
+	TransactionOrderByEnumHeightDesc TransactionOrderByEnum = "height_DESC"
+	TransactionOrderByEnumTimeAsc    TransactionOrderByEnum = "time_ASC"
+	TransactionOrderByEnumTimeDesc   TransactionOrderByEnum = "time_DESC"
+)
+
+var AllTransactionOrderByEnum = []TransactionOrderByEnum{
+	TransactionOrderByEnumHeightAsc,
+	TransactionOrderByEnumHeightDesc,
+	TransactionOrderByEnumTimeAsc,
+	TransactionOrderByEnumTimeDesc,
+}
+
+func (e TransactionOrderByEnum) IsValid() bool {
+	switch e {
+	case TransactionOrderByEnumHeightAsc, TransactionOrderByEnumHeightDesc, TransactionOrderByEnumTimeAsc, TransactionOrderByEnumTimeDesc:
+		return true
+	}
+	return false
+}
+
+func (e TransactionOrderByEnum) String() string {
+	return string(e)
+}
+
+func (e *TransactionOrderByEnum) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TransactionOrderByEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TransactionOrderByENUM", str)
+	}
+	return nil
+}
+
+func (e TransactionOrderByEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
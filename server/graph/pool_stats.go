@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+)
+
+type poolStatsLoaderKey struct{}
+
+type poolKey struct {
+	chainID string
+	poolID  uint64
+}
+
+// poolStatsLoader loads the stats of the pools of a request. The pools of a
+// list are added before their stats are resolved, the first stats resolved
+// load the stats of all the added pools with one query per chain.
+type poolStatsLoader struct {
+	load func(chainID string, poolIDs []uint64) ([]*modelv2.PoolStats, error)
+
+	mu      sync.Mutex
+	pending map[poolKey]struct{}
+	stats   map[poolKey]*modelv2.PoolStats
+}
+
+func newPoolStatsLoader(load func(chainID string, poolIDs []uint64) ([]*modelv2.PoolStats, error)) *poolStatsLoader {
+	return &poolStatsLoader{
+		load:    load,
+		pending: make(map[poolKey]struct{}),
+		stats:   make(map[poolKey]*modelv2.PoolStats),
+	}
+}
+
+// WithPoolStatsLoader returns ctx with the pool stats loader of a request.
+func WithPoolStatsLoader(ctx context.Context) context.Context {
+	return context.WithValue(ctx, poolStatsLoaderKey{}, newPoolStatsLoader(repository.NewPoolStatsRepository().FindByPoolIDs))
+}
+
+func poolStatsLoaderFromContext(ctx context.Context) (*poolStatsLoader, bool) {
+	loader, ok := ctx.Value(poolStatsLoaderKey{}).(*poolStatsLoader)
+	return loader, ok
+}
+
+// add adds the pools whose stats are loaded by the next get.
+func (l *poolStatsLoader) add(pools ...*modelv2.Pool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, pool := range pools {
+		key := poolKey{chainID: pool.ChainID, poolID: pool.PoolID}
+		if _, ok := l.stats[key]; !ok {
+			l.pending[key] = struct{}{}
+		}
+	}
+}
+
+// get returns the stats of pool, nil when they have not been computed.
+func (l *poolStatsLoader) get(pool *modelv2.Pool) (*modelv2.PoolStats, error) {
+	l.add(pool)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := poolKey{chainID: pool.ChainID, poolID: pool.PoolID}
+	if _, ok := l.pending[key]; ok {
+		if err := l.loadPending(); err != nil {
+			return nil, err
+		}
+	}
+
+	return l.stats[key], nil
+}
+
+func (l *poolStatsLoader) loadPending() error {
+	chains := make(map[string][]uint64)
+	for key := range l.pending {
+		chains[key.chainID] = append(chains[key.chainID], key.poolID)
+	}
+
+	for chainID, poolIDs := range chains {
+		stats, err := l.load(chainID, poolIDs)
+		if err != nil {
+			return err
+		}
+
+		// the pools without stats are loaded too
+		for _, poolID := range poolIDs {
+			key := poolKey{chainID: chainID, poolID: poolID}
+			l.stats[key] = nil
+			delete(l.pending, key)
+		}
+		for _, s := range stats {
+			l.stats[poolKey{chainID: s.ChainID, poolID: s.PoolID}] = s
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStatsLoader(t *testing.T) {
+	var mu sync.Mutex
+	queries := make(map[string]int)
+
+	loader := newPoolStatsLoader(func(chainID string, poolIDs []uint64) ([]*modelv2.PoolStats, error) {
+		mu.Lock()
+		queries[chainID]++
+		mu.Unlock()
+
+		stats := make([]*modelv2.PoolStats, 0)
+		for _, poolID := range poolIDs {
+			// the even pools have no stats
+			if poolID%2 == 1 {
+				stats = append(stats, &modelv2.PoolStats{ChainID: chainID, PoolID: poolID, Tvl: float64(poolID)})
+			}
+		}
+
+		return stats, nil
+	})
+
+	pools := make([]*modelv2.Pool, 0)
+	for i := uint64(1); i <= 10; i++ {
+		pools = append(pools, &modelv2.Pool{ChainID: "osmosis-1", PoolID: i})
+	}
+	loader.add(pools...)
+
+	var wg sync.WaitGroup
+	for _, pool := range pools {
+		wg.Add(1)
+		go func(pool *modelv2.Pool) {
+			defer wg.Done()
+
+			stats, err := loader.get(pool)
+			require.NoError(t, err)
+
+			if pool.PoolID%2 == 0 {
+				require.Nil(t, stats)
+				return
+			}
+			require.Equal(t, float64(pool.PoolID), stats.Tvl)
+		}(pool)
+	}
+	wg.Wait()
+
+	require.Equal(t, map[string]int{"osmosis-1": 1}, queries)
+
+	// a pool of another chain, not added, is loaded alone
+	stats, err := loader.get(&modelv2.Pool{ChainID: "testnet", PoolID: 1})
+	require.NoError(t, err)
+	require.Equal(t, "testnet", stats.ChainID)
+	require.Equal(t, map[string]int{"osmosis-1": 1, "testnet": 1}, queries)
+}
@@ -1,12 +1,19 @@
 package graph
 
-import "github.com/angelorc/sinfonia-go/config"
+import (
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/server/auth"
+)
 
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
+// Resolver holds the dependencies of the resolvers, the principal of a
+// request is read from the context with auth.PrincipalFromContext and its
+// role on a resource is given by Roles.
 type Resolver struct {
-	Token  *string
-	Config config.Config
+	Config  config.Config
+	Roles   *auth.Roles
+	Bitsong MerkledropQuerier
 }
@@ -7,59 +7,140 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/angelorc/sinfonia-go/mongo/model"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/mongo/types"
 	"github.com/angelorc/sinfonia-go/server/graph/generated"
+	model1 "github.com/angelorc/sinfonia-go/server/graph/model"
 	"github.com/angelorc/sinfonia-go/server/util"
 	"github.com/angelorc/sinfonia-go/utility"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (r *mutationResolver) UpdateMerkledrop(ctx context.Context, id int, data model.MerkledropUpdateReq) (*model.Merkledrop, error) {
+func (r *accountResolver) Addresses(ctx context.Context, obj *modelv2.Account) ([]*modelv2.AccountAddress, error) {
+	return r.accountAddresses(obj.Address), nil
+}
+
+func (r *accountResolver) Activity(ctx context.Context, obj *modelv2.Account) (*modelv2.AccountActivity, error) {
+	addresses := r.accountAddresses(obj.Address)
+	list := addressList(addresses)
+
+	swaps, err := repository.NewSwapRepository().SumByAccounts(list)
+	if err != nil {
+		return nil, err
+	}
+
+	liquidity, err := repository.NewLiquidityRepository().SumBySenders(list)
+	if err != nil {
+		return nil, err
+	}
+
+	incentives, err := repository.NewIncentiveRepository().SumByReceivers(list)
+	if err != nil {
+		return nil, err
+	}
+
+	bitsongAddress := chainAddress(addresses, r.Config.Bitsong)
+	msgType := msgTypeIssueFantoken
+	issued, err := repository.NewMessageRepository().Count(&modelv2.MessageFilter{ChainID: &r.Config.Bitsong.ChainID, MsgType: &msgType, Signer: &bitsongAddress})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := new(model.MerkledropProof).Count(claimedProofs(list))
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelv2.AccountActivity{
+		Swaps:            *swaps,
+		Liquidity:        *liquidity,
+		Incentives:       *incentives,
+		FantokensIssued:  issued,
+		MerkledropClaims: int64(claims),
+	}, nil
+}
+
+func (r *accountResolver) Fantokens(ctx context.Context, obj *modelv2.Account) ([]*modelv2.Fantoken, error) {
+	owner := chainAddress(r.accountAddresses(obj.Address), r.Config.Bitsong)
+
+	return repository.NewFantokenRepository().Find(&types.FantokenFilter{Owner: &owner}, &types.PaginationReq{})
+}
+
+func (r *accountResolver) MerkledropClaims(ctx context.Context, obj *modelv2.Account) ([]*model.MerkledropProof, error) {
+	list := addressList(r.accountAddresses(obj.Address))
+
+	return new(model.MerkledropProof).List(claimedProofs(list), nil, nil, nil, nil)
+}
+
+func (r *accountResolver) LiquidityPositions(ctx context.Context, obj *modelv2.Account) ([]*modelv2.LiquidityPosition, error) {
+	return liquidityPositions(chainAddress(r.accountAddresses(obj.Address), r.Config.Osmosis), nil)
+}
+
+func (r *mutationResolver) UpdateMerkledrop(ctx context.Context, id int, data model.MerkledropUpdateReq) (*model1.MerkledropUpdateResult, error) {
 	item := model.Merkledrop{}
+	result := &model1.MerkledropUpdateResult{}
 
 	// Validate
 	if id <= 0 {
-		return &item, errors.New("invalid merkledrop_id")
+		return result, errors.New("invalid merkledrop_id")
 	}
 	if err := utility.ValidateStruct(data); err != nil {
-		return &item, err
+		return result, err
 	}
 
-	dataUpdate := model.MerkledropUpdate{}
-	dataUpdate.Name = data.Name
+	principal, role, md, err := r.authorizeMerkledrop(ctx, uint64(id))
+	if err != nil {
+		return result, err
+	}
 
-	// Upload Image
-	if data.Image != nil {
-		imageUrl, err := util.UploadImage(r.Config.Cloudflare, data)
-		if err != nil {
-			return &item, err
-		}
+	mdID := int64(id)
+	current := model.Merkledrop{}
+	current.One(&model.MerkledropWhere{MerkledropID: &mdID})
 
-		dataUpdate.Image = imageUrl
-	}
+	// Verify List, nothing is written when it is not valid
+	proof := model.MerkledropProof{}
+	dataProofs := make([]model.MerkledropProofCreate, 0)
+	stored := 0
 
-	// Store List
 	if data.List != nil && data.List.Size > 0 {
 		parsedList, err := parseMerkleProofsList(data.List.File)
 		if err != nil {
-			return &item, err
+			return result, err
+		}
+
+		if md == nil {
+			if md, err = r.queryMerkledrop(ctx, uint64(id)); err != nil {
+				return result, err
+			}
 		}
 
-		proof := model.MerkledropProof{}
-		dataProofs := make([]model.MerkledropProofCreate, 0)
+		result.Report, err = verifyMerkledropProofs(md, r.Config.Bitsong.AccountPrefix, parsedList)
+		if err != nil {
+			return result, err
+		}
+		if !result.Report.Valid {
+			return result, nil
+		}
 
-		if err := proof.CreateIndexes(); err != nil {
-			return &item, err
+		stored, err = proof.Count(&model.MerkledropProofWhere{MerkledropID: &mdID})
+		if err != nil {
+			return result, err
+		}
+		if stored > 0 {
+			return result, fmt.Errorf("merkledrop %d already has %d proofs", id, stored)
 		}
 
 		for addr, r := range parsedList {
 			amount, err := strconv.ParseInt(r.Amount, 10, 64)
 			if err != nil {
-				return &item, err
+				return result, err
 			}
 
 			dataProof := model.MerkledropProofCreate{
@@ -74,107 +155,311 @@ func (r *mutationResolver) UpdateMerkledrop(ctx context.Context, id int, data mo
 
 			dataProofs = append(dataProofs, dataProof)
 		}
+	}
+
+	// the proofs are stored in index order
+	sort.Slice(dataProofs, func(i, j int) bool { return dataProofs[i].Index < dataProofs[j].Index })
+
+	dataUpdate := model.MerkledropUpdate{}
+	dataUpdate.Name = data.Name
+
+	changes := make([]modelv2.AuditChange, 0)
+	if current.Name != data.Name {
+		changes = append(changes, modelv2.AuditChange{Field: "name", From: current.Name, To: data.Name})
+	}
+	imageChange := -1
+	if data.Image != nil {
+		imageChange = len(changes)
+		changes = append(changes, modelv2.AuditChange{Field: "image", From: current.Image})
+	}
+	if len(dataProofs) > 0 {
+		changes = append(changes, modelv2.AuditChange{Field: "list", From: stored, To: len(dataProofs)})
+	}
+
+	// the change is recorded as pending before anything is written, its
+	// outcome is recorded once it is done
+	auditRepo := repository.NewAuditLogRepository()
+	auditLog := &modelv2.AuditLog{
+		Actor:    principal.Address,
+		Role:     string(role),
+		Action:   "updateMerkledrop",
+		Target:   "merkledrop",
+		TargetID: strconv.Itoa(id),
+		Changes:  changes,
+		Status:   modelv2.AuditStatusPending,
+	}
+	if err := auditRepo.Create(auditLog); err != nil {
+		return result, fmt.Errorf("failed to store the audit log of merkledrop %d: %w", id, err)
+	}
+
+	finish := func(err error) error {
+		auditLog.Status = modelv2.AuditStatusSucceeded
+		if err != nil {
+			auditLog.Status = modelv2.AuditStatusFailed
+			auditLog.Error = err.Error()
+		}
+
+		if outcomeErr := auditRepo.SetOutcome(auditLog); outcomeErr != nil && err == nil {
+			return fmt.Errorf("failed to store the audit log outcome of merkledrop %d: %w", id, outcomeErr)
+		}
+
+		return err
+	}
+
+	// Upload Image
+	if data.Image != nil {
+		imageUrl, err := util.UploadImage(r.Config.Cloudflare, data)
+		if err != nil {
+			return result, finish(err)
+		}
+
+		dataUpdate.Image = imageUrl
+		auditLog.Changes[imageChange].To = imageUrl
+	}
+
+	// Store List, the unique index on the merkledrop and address makes a
+	// concurrent upload fail
+	if len(dataProofs) > 0 {
+		if err := proof.CreateIndexes(); err != nil {
+			return result, finish(err)
+		}
 
 		if err := proof.StoreMany(dataProofs); err != nil {
-			return &item, err
+			if errors.Is(err, model.ErrDuplicateProof) {
+				err = fmt.Errorf("merkledrop %d already has proofs", id)
+			}
+
+			return result, finish(err)
 		}
 	}
 
 	if err := item.Update(int64(id), &dataUpdate); err != nil {
-		return &item, err
+		return result, finish(err)
 	}
+	if err := finish(nil); err != nil {
+		return result, err
+	}
+	result.Merkledrop = &item
 
-	return &item, nil
+	return result, nil
 }
 
-func (r *queryResolver) Transaction(ctx context.Context, where *model.TransactionWhere) (*model.Transaction, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *poolResolver) Stats(ctx context.Context, obj *modelv2.Pool) (*modelv2.PoolStats, error) {
+	if loader, ok := poolStatsLoaderFromContext(ctx); ok {
+		return loader.get(obj)
+	}
+
+	stats := repository.NewPoolStatsRepository().FindByPoolID(obj.ChainID, obj.PoolID)
+	if stats.ID.IsZero() {
+		return nil, nil
+	}
+
+	return stats, nil
 }
 
-func (r *queryResolver) Transactions(ctx context.Context, where *model.TransactionWhere, in []*primitive.ObjectID, orderBy *model.TransactionOrderByENUM, skip *int, limit *int) ([]*model.Transaction, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Transaction(ctx context.Context, where *modelv2.TransactionFilter) (*modelv2.Transaction, error) {
+	item := repository.NewTransactionRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
 }
 
-func (r *queryResolver) TransactionCount(ctx context.Context, where *model.TransactionWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Transactions(ctx context.Context, where *modelv2.TransactionFilter, orderBy *model1.TransactionOrderByEnum, first *int, after *string) (*model1.TransactionConnection, error) {
+	if where == nil {
+		where = &modelv2.TransactionFilter{}
+	}
+
+	pagination := newPaginationReq(orderBy, model1.TransactionOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewTransactionRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model1.TransactionEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.TransactionEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.TransactionConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
-func (r *queryResolver) Message(ctx context.Context, where *model.MessageWhere) (*model.Message, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) TransactionCount(ctx context.Context, where *modelv2.TransactionFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.TransactionFilter{}
+	}
+
+	return countResult(repository.NewTransactionRepository().Count(where))
 }
 
-func (r *queryResolver) Messages(ctx context.Context, where *model.MessageWhere, in []*primitive.ObjectID, orderBy *model.MessageOrderByENUM, skip *int, limit *int) ([]*model.Message, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Message(ctx context.Context, where *modelv2.MessageFilter) (*modelv2.Message, error) {
+	item := repository.NewMessageRepository().FindOne(where)
+	if item.TxHash == "" {
+		return nil, nil
+	}
+
+	return item, nil
 }
 
-func (r *queryResolver) MessageCount(ctx context.Context, where *model.MessageWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Messages(ctx context.Context, where *modelv2.MessageFilter, orderBy *model1.MessageOrderByEnum, first *int, after *string) (*model1.MessageConnection, error) {
+	pagination := newPaginationReq(orderBy, model1.MessageOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewMessageRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "tx_id", "msg_index")
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model1.MessageEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.MessageEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.MessageConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+func (r *queryResolver) MessageCount(ctx context.Context, where *modelv2.MessageFilter) (*int, error) {
+	return countResult(repository.NewMessageRepository().Count(where))
 }
 
-func (r *queryResolver) Account(ctx context.Context, where *model.AccountWhere) (*model.Account, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Account(ctx context.Context, where *modelv2.AccountFilter) (*modelv2.Account, error) {
+	if where != nil {
+		where.AddressKey = addressKey(where.AddressKey)
+	}
+
+	item := repository.NewAccountRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
 }
 
-func (r *queryResolver) Accounts(ctx context.Context, where *model.AccountWhere, in []*primitive.ObjectID, orderBy *model.AccountOrderByENUM, skip *int, limit *int) ([]*model.Account, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Accounts(ctx context.Context, where *modelv2.AccountFilter, orderBy *model1.AccountOrderByEnum, first *int, after *string) (*model1.AccountConnection, error) {
+	if where == nil {
+		where = &modelv2.AccountFilter{}
+	}
+	where.AddressKey = addressKey(where.AddressKey)
+
+	pagination := newPaginationReq(orderBy, model1.AccountOrderByEnumFirstSeenDesc, first, after)
+
+	items, err := repository.NewAccountRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model1.AccountEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.AccountEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.AccountConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
-func (r *queryResolver) AccountCount(ctx context.Context, where *model.AccountWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) AccountCount(ctx context.Context, where *modelv2.AccountFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.AccountFilter{}
+	}
+	where.AddressKey = addressKey(where.AddressKey)
+
+	return countResult(repository.NewAccountRepository().Count(where))
 }
 
-func (r *queryResolver) Fantoken(ctx context.Context, where *model.FantokenWhere) (*model.Fantoken, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Fantoken(ctx context.Context, where *types.FantokenFilter) (*modelv2.Fantoken, error) {
+	item := repository.NewFantokenRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
 }
 
-func (r *queryResolver) Fantokens(ctx context.Context, where *model.FantokenWhere, in []*primitive.ObjectID, orderBy *model.FantokenOrderByENUM, skip *int, limit *int) ([]*model.Fantoken, error) {
+func (r *queryResolver) Fantokens(ctx context.Context, where *types.FantokenFilter, orderBy *model1.FantokenOrderByEnum, first *int, after *string) (*model1.FantokenConnection, error) {
 	if where == nil {
-		where = &model.FantokenWhere{}
+		where = &types.FantokenFilter{}
 	}
 
-	var customQuery *primitive.M
-	if in != nil {
-		q := bson.M{"_id": bson.M{"$in": in}}
-		customQuery = &q
+	pagination := newPaginationReq(orderBy, model1.FantokenOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewFantokenRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
 	}
 
-	item := model.Fantoken{}
-	items, err := item.List(where, orderBy, skip, limit, customQuery)
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
 	if err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	edges := make([]*model1.FantokenEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.FantokenEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.FantokenConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
-func (r *queryResolver) FantokenCount(ctx context.Context, where *model.FantokenWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) FantokenCount(ctx context.Context, where *types.FantokenFilter) (*int, error) {
+	if where == nil {
+		where = &types.FantokenFilter{}
+	}
+
+	return countResult(repository.NewFantokenRepository().Count(where))
 }
 
 func (r *queryResolver) Merkledrop(ctx context.Context, where *model.MerkledropWhere) (*model.Merkledrop, error) {
-	panic(fmt.Errorf("not implemented"))
+	if where == nil {
+		where = &model.MerkledropWhere{}
+	}
+
+	item := model.Merkledrop{}
+	item.One(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return &item, nil
 }
 
-func (r *queryResolver) Merkledrops(ctx context.Context, where *model.MerkledropWhere, in []*primitive.ObjectID, orderBy *model.MerkledropOrderByENUM, skip *int, limit *int) ([]*model.Merkledrop, error) {
+func (r *queryResolver) Merkledrops(ctx context.Context, where *model.MerkledropWhere, in []*primitive.ObjectID, orderBy *model1.MerkledropOrderByEnum, first *int, after *string) (*model1.MerkledropConnection, error) {
 	if where == nil {
 		where = &model.MerkledropWhere{}
 	}
 
-	var customQuery *primitive.M
-	if in != nil {
-		q := bson.M{"_id": bson.M{"$in": in}}
-		customQuery = &q
+	pagination := newPaginationReq(orderBy, model1.MerkledropOrderByEnumHeightDesc, first, after)
+
+	items, err := new(model.Merkledrop).Find(where, in, pagination)
+	if err != nil {
+		return nil, err
 	}
 
-	item := model.Merkledrop{}
-	items, err := item.List(where, orderBy, skip, limit, customQuery)
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
 	if err != nil {
 		return nil, err
 	}
 
-	// check on-chain then remove if is claimed
+	edges := make([]*model1.MerkledropEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.MerkledropEdge{Cursor: cursors[i], Node: item}
+	}
 
-	return items, nil
+	return &model1.MerkledropConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
 func (r *queryResolver) MerkledropCount(ctx context.Context, where *model.MerkledropWhere) (*int, error) {
@@ -193,6 +478,7 @@ func (r *queryResolver) MerkledropProof(ctx context.Context, where *model.Merkle
 	if where == nil {
 		where = &model.MerkledropProofWhere{}
 	}
+	where.AddressKey = addressKey(where.AddressKey)
 
 	item := model.MerkledropProof{}
 	item.One(where)
@@ -202,24 +488,30 @@ func (r *queryResolver) MerkledropProof(ctx context.Context, where *model.Merkle
 	return &item, nil
 }
 
-func (r *queryResolver) MerkledropProofs(ctx context.Context, where *model.MerkledropProofWhere, in []*primitive.ObjectID, orderBy *model.MerkledropProofOrderByENUM, skip *int, limit *int) ([]*model.MerkledropProof, error) {
+func (r *queryResolver) MerkledropProofs(ctx context.Context, where *model.MerkledropProofWhere, in []*primitive.ObjectID, orderBy *model1.MerkledropProofOrderByEnum, first *int, after *string) (*model1.MerkledropProofConnection, error) {
 	if where == nil {
 		where = &model.MerkledropProofWhere{}
 	}
+	where.AddressKey = addressKey(where.AddressKey)
 
-	var customQuery *primitive.M
-	if in != nil {
-		q := bson.M{"_id": bson.M{"$in": in}}
-		customQuery = &q
+	pagination := newPaginationReq(orderBy, model1.MerkledropProofOrderByEnumCreatedAtDesc, first, after)
+
+	items, err := new(model.MerkledropProof).Find(where, in, pagination)
+	if err != nil {
+		return nil, err
 	}
 
-	item := model.MerkledropProof{}
-	items, err := item.List(where, orderBy, skip, limit, customQuery)
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
 	if err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	edges := make([]*model1.MerkledropProofEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.MerkledropProofEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.MerkledropProofConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
 func (r *queryResolver) MerkledropProofCount(ctx context.Context, where *model.MerkledropProofWhere) (*int, error) {
@@ -227,6 +519,7 @@ func (r *queryResolver) MerkledropProofCount(ctx context.Context, where *model.M
 	if where == nil {
 		where = &model.MerkledropProofWhere{}
 	}
+	where.AddressKey = addressKey(where.AddressKey)
 	count, err := m.Count(where)
 	if err != nil {
 		return nil, err
@@ -234,63 +527,334 @@ func (r *queryResolver) MerkledropProofCount(ctx context.Context, where *model.M
 	return &count, nil
 }
 
-func (r *queryResolver) Incentive(ctx context.Context, where *model.IncentiveWhere) (*model.Incentive, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Incentive(ctx context.Context, where *modelv2.IncentiveFilter) (*modelv2.Incentive, error) {
+	item := repository.NewIncentiveRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+func (r *queryResolver) Incentives(ctx context.Context, where *modelv2.IncentiveFilter, orderBy *model1.IncentiveOrderByEnum, first *int, after *string) (*model1.IncentiveConnection, error) {
+	if where == nil {
+		where = &modelv2.IncentiveFilter{}
+	}
+
+	pagination := newPaginationReq(orderBy, model1.IncentiveOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewIncentiveRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model1.IncentiveEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.IncentiveEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.IncentiveConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
-func (r *queryResolver) Incentives(ctx context.Context, where *model.IncentiveWhere, in []*primitive.ObjectID, orderBy *model.IncentiveOrderByENUM, skip *int, limit *int) ([]*model.Incentive, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) IncentiveCount(ctx context.Context, where *modelv2.IncentiveFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.IncentiveFilter{}
+	}
+
+	return countResult(repository.NewIncentiveRepository().Count(where))
+}
+
+func (r *queryResolver) Swap(ctx context.Context, where *modelv2.SwapFilter) (*modelv2.Swap, error) {
+	if where != nil {
+		where.AccountKey = addressKey(where.AccountKey)
+	}
+
+	item := repository.NewSwapRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+func (r *queryResolver) Swaps(ctx context.Context, where *modelv2.SwapFilter, orderBy *model1.SwapOrderByEnum, first *int, after *string) (*model1.SwapConnection, error) {
+	if where == nil {
+		where = &modelv2.SwapFilter{}
+	}
+	where.AccountKey = addressKey(where.AccountKey)
+
+	pagination := newPaginationReq(orderBy, model1.SwapOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewSwapRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model1.SwapEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.SwapEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.SwapConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+func (r *queryResolver) SwapCount(ctx context.Context, where *modelv2.SwapFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.SwapFilter{}
+	}
+	where.AccountKey = addressKey(where.AccountKey)
+
+	return countResult(repository.NewSwapRepository().Count(where))
+}
+
+func (r *queryResolver) Pool(ctx context.Context, where *modelv2.PoolFilter) (*modelv2.Pool, error) {
+	item := repository.NewPoolRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+func (r *queryResolver) Pools(ctx context.Context, where *modelv2.PoolFilter, orderBy *model1.PoolOrderByEnum, first *int, after *string) (*model1.PoolConnection, error) {
+	if where == nil {
+		where = &modelv2.PoolFilter{}
+	}
+
+	pagination := newPaginationReq(orderBy, model1.PoolOrderByEnumHeightDesc, first, after)
+
+	items, err := repository.NewPoolRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
+	if err != nil {
+		return nil, err
+	}
+
+	// the stats of the page are loaded at once
+	if loader, ok := poolStatsLoaderFromContext(ctx); ok {
+		loader.add(items...)
+	}
+
+	edges := make([]*model1.PoolEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.PoolEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.PoolConnection{Edges: edges, PageInfo: pageInfo}, nil
 }
 
-func (r *queryResolver) IncentiveCount(ctx context.Context, where *model.IncentiveWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) PoolCount(ctx context.Context, where *modelv2.PoolFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.PoolFilter{}
+	}
+
+	return countResult(repository.NewPoolRepository().Count(where))
 }
 
-func (r *queryResolver) Swap(ctx context.Context, where *model.SwapWhere) (*model.Swap, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) PoolStats(ctx context.Context, where *modelv2.PoolStatsFilter) ([]*modelv2.PoolStats, error) {
+	return repository.NewPoolStatsRepository().Find(where)
 }
 
-func (r *queryResolver) Swaps(ctx context.Context, where *model.SwapWhere, in []*primitive.ObjectID, orderBy *model.SwapOrderByENUM, skip *int, limit *int) ([]*model.Swap, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Leaderboard(ctx context.Context, chainID *string, metric string, window string, poolID *int, limit *int) ([]*modelv2.LeaderboardEntry, error) {
+	filter, err := modelv2.NewLeaderboardFilter(metric, window, poolID, limit, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	filter.ChainID = LeaderboardChainID(r.Config, filter.Metric, chainID)
+
+	return repository.NewLeaderboardRepository().Top(filter)
 }
 
-func (r *queryResolver) SwapCount(ctx context.Context, where *model.SwapWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) Candles(ctx context.Context, chainID *string, poolID int, interval string, from time.Time, to time.Time) ([]*modelv2.Candle, error) {
+	candleInterval, err := modelv2.ParseCandleInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if to.Before(from) {
+		return nil, errors.New("invalid time range")
+	}
+	if to.Sub(from)/candleInterval.Duration() > maxCandles {
+		return nil, fmt.Errorf("too many candles, the range can contain at most %d intervals", maxCandles)
+	}
+
+	chain := r.Config.Osmosis.ChainID
+	if chainID != nil {
+		chain = *chainID
+	}
+
+	pid := uint64(poolID)
+	pool := repository.NewPoolRepository().FindOne(&modelv2.PoolFilter{ChainID: &chain, PoolID: &pid})
+	if pool.ID.IsZero() {
+		return nil, fmt.Errorf("pool %d not found on %s", poolID, chain)
+	}
+
+	from = candleInterval.Start(from)
+
+	// the candles of the intervals not closed when the candles were synced
+	// are built from the swaps
+	tailFrom := from
+	if synced := candlesSyncTime(pool.ChainID); !synced.IsZero() {
+		if start := candleInterval.Start(synced); start.After(tailFrom) {
+			tailFrom = start
+		}
+	}
+
+	stored, err := repository.NewCandleRepository().Find(pool.ChainID, pool.PoolID, candleInterval, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// the candles stored by a sync running after the checkpoint was read are
+	// built from the swaps too
+	candles := make([]*modelv2.Candle, 0, len(stored))
+	for _, candle := range stored {
+		if !candle.Time.Before(tailFrom) {
+			break
+		}
+		candles = append(candles, candle)
+	}
+
+	if !tailFrom.After(to) {
+		id := int64(pool.PoolID)
+		swaps, err := repository.NewSwapRepository().FindByTime(pool.ChainID, &id, tailFrom, candleInterval.Start(to).Add(candleInterval.Duration()))
+		if err != nil {
+			return nil, err
+		}
+
+		meta := modelv2.CandleMeta{ChainID: pool.ChainID, PoolID: pool.PoolID, Interval: candleInterval}
+		candles = append(candles, modelv2.NewCandles(meta, swaps)...)
+	}
+
+	return candles, nil
 }
 
-func (r *queryResolver) Pool(ctx context.Context, where *model.PoolWhere) (*model.Pool, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *queryResolver) LiquidityEvent(ctx context.Context, where *modelv2.LiquidityEventFilter) (*modelv2.LiquidityEvent, error) {
+	if where != nil {
+		where.Time = timeFilter(where.Time)
+		where.SenderKey = addressKey(where.SenderKey)
+	}
+
+	item := repository.NewLiquidityRepository().FindOne(where)
+	if item.ID.IsZero() {
+		return nil, nil
+	}
+
+	return item, nil
 }
 
-func (r *queryResolver) Pools(ctx context.Context, where *model.PoolWhere, in []*primitive.ObjectID, orderBy *model.PoolOrderByENUM, skip *int, limit *int) ([]*model.Pool, error) {
+func (r *queryResolver) LiquidityEvents(ctx context.Context, where *modelv2.LiquidityEventFilter, orderBy *model1.LiquidityEventOrderByEnum, first *int, after *string) (*model1.LiquidityEventConnection, error) {
 	if where == nil {
-		where = &model.PoolWhere{}
+		where = &modelv2.LiquidityEventFilter{}
 	}
+	where.Time = timeFilter(where.Time)
+	where.SenderKey = addressKey(where.SenderKey)
+
+	pagination := newPaginationReq(orderBy, model1.LiquidityEventOrderByEnumHeightDesc, first, after)
 
-	var customQuery *primitive.M
-	if in != nil {
-		q := bson.M{"_id": bson.M{"$in": in}}
-		customQuery = &q
+	items, err := repository.NewLiquidityRepository().Find(where, pagination)
+	if err != nil {
+		return nil, err
 	}
 
-	item := model.Pool{}
-	items, err := item.List(where, orderBy, skip, limit, customQuery)
+	items, cursors, pageInfo, err := connectionPage(items, pagination, "_id")
 	if err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	edges := make([]*model1.LiquidityEventEdge, len(items))
+	for i, item := range items {
+		edges[i] = &model1.LiquidityEventEdge{Cursor: cursors[i], Node: item}
+	}
+
+	return &model1.LiquidityEventConnection{Edges: edges, PageInfo: pageInfo}, nil
+}
+
+func (r *queryResolver) LiquidityEventCount(ctx context.Context, where *modelv2.LiquidityEventFilter) (*int, error) {
+	if where == nil {
+		where = &modelv2.LiquidityEventFilter{}
+	}
+	where.Time = timeFilter(where.Time)
+	where.SenderKey = addressKey(where.SenderKey)
+
+	return countResult(repository.NewLiquidityRepository().Count(where))
+}
+
+func (r *queryResolver) LiquidityPositions(ctx context.Context, sender string, poolID *int) ([]*modelv2.LiquidityPosition, error) {
+	var id *uint64
+	if poolID != nil {
+		poolID := uint64(*poolID)
+		id = &poolID
+	}
+
+	return liquidityPositions(sender, id)
+}
+
+func (r *queryResolver) PoolLiquidity(ctx context.Context, poolID int, from time.Time, to time.Time) ([]*modelv2.HistoricalLiquidity, error) {
+	if to.Before(from) {
+		return nil, errors.New("invalid time range")
+	}
+	if to.Sub(from)/modelv2.HistoricalLiquidityInterval > maxSnapshots {
+		return nil, fmt.Errorf("too many snapshots, the range can contain at most %d intervals", maxSnapshots)
+	}
+
+	return repository.NewHistoricalLiquidityRepository().Find(uint64(poolID), from, to)
+}
+
+func (r *subscriptionResolver) NewBlock(ctx context.Context, chainID *string) (<-chan *modelv2.Block, error) {
+	return subscribe(ctx, pubsub.TopicBlocks, func(block *modelv2.Block) bool {
+		return matchString(chainID, block.ChainID)
+	}), nil
+}
+
+func (r *subscriptionResolver) NewSwap(ctx context.Context, chainID *string, poolID *int) (<-chan *modelv2.Swap, error) {
+	return subscribe(ctx, pubsub.TopicSwaps, func(swap *modelv2.Swap) bool {
+		return matchString(chainID, swap.ChainID) && matchInt(poolID, swap.PoolId)
+	}), nil
 }
 
-func (r *queryResolver) PoolCount(ctx context.Context, where *model.PoolWhere) (*int, error) {
-	panic(fmt.Errorf("not implemented"))
+func (r *subscriptionResolver) NewLiquidityEvent(ctx context.Context, chainID *string, poolID *int) (<-chan *modelv2.LiquidityEvent, error) {
+	return subscribe(ctx, pubsub.TopicLiquidityEvents, func(event *modelv2.LiquidityEvent) bool {
+		return matchString(chainID, event.ChainID) && matchInt(poolID, int64(event.PoolID))
+	}), nil
 }
 
+func (r *subscriptionResolver) NewFantoken(ctx context.Context, chainID *string) (<-chan *modelv2.Fantoken, error) {
+	return subscribe(ctx, pubsub.TopicFantokens, func(fantoken *modelv2.Fantoken) bool {
+		return matchString(chainID, fantoken.ChainID)
+	}), nil
+}
+
+// Account returns generated.AccountResolver implementation.
+func (r *Resolver) Account() generated.AccountResolver { return &accountResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
+// Pool returns generated.PoolResolver implementation.
+func (r *Resolver) Pool() generated.PoolResolver { return &poolResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type accountResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type poolResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"log"
+
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// subscribe decodes the documents published on topic and sends the ones
+// matched by match, until the subscription ctx is done.
+func subscribe[T any](ctx context.Context, topic string, match func(*T) bool) <-chan *T {
+	events := pubsub.Subscribe(ctx, topic)
+	items := make(chan *T, 1)
+
+	go func() {
+		defer close(items)
+
+		for doc := range events {
+			item := new(T)
+			if err := bson.Unmarshal(doc, item); err != nil {
+				log.Printf("failed to decode %s event. err: %v", topic, err)
+				continue
+			}
+
+			if !match(item) {
+				continue
+			}
+
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return items
+}
+
+func matchString(filter *string, value string) bool {
+	return filter == nil || *filter == value
+}
+
+func matchInt(filter *int, value int64) bool {
+	return filter == nil || int64(*filter) == value
+}
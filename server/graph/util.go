@@ -4,8 +4,55 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/mongo/types"
+	model1 "github.com/angelorc/sinfonia-go/server/graph/model"
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+	maxCandles   = 5000
+	maxSnapshots = 5000
+
+	// candlesCheckpoint is the checkpoint of the candles synced by
+	// sinfonia-osmosis
+	candlesCheckpoint = "candles"
 )
 
+// candlesSyncTime returns the time of the block the candles of chainID are
+// synced to, the candles of the intervals closed before it are stored. It is
+// zero when no candle is synced.
+func candlesSyncTime(chainID string) time.Time {
+	checkpoint := repository.NewCheckpointRepository().Get(chainID, candlesCheckpoint)
+	if checkpoint.Height == 0 {
+		return time.Time{}
+	}
+
+	block := repository.NewBlockRepository().FindOne(&types.BlockFilter{ChainID: &chainID, Height: &checkpoint.Height})
+	return block.Time
+}
+
+// LeaderboardChainID returns chainID or, when it is nil, the chain the metric
+// is synced from: the claims are on bitsong, the swaps and the joins on
+// osmosis.
+func LeaderboardChainID(cfg config.Config, metric modelv2.LeaderboardMetric, chainID *string) string {
+	if chainID != nil {
+		return *chainID
+	}
+
+	if metric == modelv2.LeaderboardMetricClaims {
+		return cfg.Bitsong.ChainID
+	}
+
+	return cfg.Osmosis.ChainID
+}
+
 type ListItem struct {
 	Index  int64    `json:"index"`
 	Amount string   `json:"amount"`
@@ -27,3 +74,80 @@ func parseMerkleProofsList(list io.Reader) (List, error) {
 
 	return parsedList, nil
 }
+
+// newPaginationReq converts the connection arguments to the repositories
+// pagination, one more item than requested is fetched to know if there is a
+// next page.
+func newPaginationReq[T ~string](orderBy *T, defaultOrderBy T, first *int, after *string) *types.PaginationReq {
+	order := string(defaultOrderBy)
+	if orderBy != nil {
+		order = string(*orderBy)
+	}
+
+	l := int64(defaultLimit)
+	if first != nil && *first > 0 {
+		l = int64(*first)
+	}
+	if l > maxLimit {
+		l = maxLimit
+	}
+	l++
+
+	return &types.PaginationReq{
+		OrderBy: &order,
+		Limit:   &l,
+		After:   after,
+	}
+}
+
+// connectionPage removes the item fetched to detect the next page and
+// returns the page info and the cursor of each item, tieKeys are the sort
+// keys following the order key.
+func connectionPage[T any](items []*T, pagination *types.PaginationReq, tieKeys ...string) ([]*T, []string, *model1.PageInfo, error) {
+	pageInfo := &model1.PageInfo{
+		HasPreviousPage: pagination.After != nil,
+	}
+
+	if int64(len(items)) >= *pagination.Limit {
+		items = items[:*pagination.Limit-1]
+		pageInfo.HasNextPage = true
+	}
+
+	orderKey, _ := utility.GetOrderByKeyAndValue(*pagination.OrderBy)
+	keys := append([]string{orderKey}, tieKeys...)
+
+	cursors := make([]string, len(items))
+	for i, item := range items {
+		cursor, err := types.NewCursor(item, keys...)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		cursors[i] = cursor.Encode()
+	}
+
+	if len(cursors) > 0 {
+		pageInfo.StartCursor = &cursors[0]
+		pageInfo.EndCursor = &cursors[len(cursors)-1]
+	}
+
+	return items, cursors, pageInfo, nil
+}
+
+func countResult(count int64, err error) (*int, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	c := int(count)
+	return &c, nil
+}
+
+// timeFilter returns nil for a filter without bounds, that would match no time.
+func timeFilter(filter *modelv2.TimeFilter) *modelv2.TimeFilter {
+	if filter == nil || (filter.Gte == nil && filter.Lt == nil) {
+		return nil
+	}
+
+	return filter
+}
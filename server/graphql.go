@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
@@ -10,66 +9,87 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/server/auth"
+	"github.com/angelorc/sinfonia-go/server/chain"
 	"github.com/angelorc/sinfonia-go/server/graph"
 	"github.com/angelorc/sinfonia-go/server/graph/generated"
 	w3t "github.com/angelorc/sinfonia-go/server/web3token"
-	"github.com/angelorc/sinfonia-go/utility"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo"
+	"github.com/vektah/gqlparser/v2/ast"
+	"log"
+	"time"
 )
 
-var token string
-var playgroundPassword string
-var submissionToken string
-
-// Get header value and add to gql resolvers
-func getHeaders(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(ctx echo.Context) error {
-		headers := ctx.Request().Header
-		token = utility.GetHeaderString("Authorization", headers)
-		playgroundPassword = utility.GetHeaderString("Playground-Password", headers)
-
-		return next(ctx)
+// authDirective resolves the fields of @auth for the requests authenticated
+// by the auth middleware only.
+func authDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if _, ok := auth.PrincipalFromContext(ctx); !ok {
+		return nil, auth.ErrUnauthenticated
 	}
+
+	return next(ctx)
 }
 
 func InitGraphql(cfg config.Config, e *echo.Echo) {
 	// Resolvers && Directives
-	resolver := graph.Resolver{Token: &token, Config: cfg}
-	config := generated.Config{Resolvers: &resolver}
-	config.Directives.Auth = func(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
-		web3token, err := w3t.NewWeb3TokenFromBearer(*resolver.Token)
-		if err != nil {
-			return nil, fmt.Errorf("access denied")
-		}
+	roles, err := auth.NewRoles(cfg.GraphQL.Auth.Admins)
+	if err != nil {
+		log.Fatalf("invalid graphql auth admins: %v", err)
+	}
 
-		if web3token.GetDomain() != "test.com" {
-			return nil, fmt.Errorf("access denied")
-		}
+	bitsong, err := chain.NewClient(&cfg.Bitsong)
+	if err != nil {
+		log.Fatalf("failed to connect to the bitsong grpc %s: %v", cfg.Bitsong.GRPCAddr, err)
+	}
 
-		return next(ctx)
+	resolver := graph.Resolver{Config: cfg, Roles: roles, Bitsong: bitsong}
+	config := generated.Config{Resolvers: &resolver}
+	config.Directives.Auth = authDirective
+
+	nonceTTL, _ := cfg.GraphQL.Auth.GetNonceTTL()
+	nonces := repository.NewAuthNonceRepository()
+	if len(cfg.GraphQL.Auth.Domains) == 0 {
+		log.Printf("no graphql auth domains configured, the @auth fields are denied")
 	}
 
-	e.Use(getHeaders)
+	verifier := &w3t.Verifier{
+		Domains:      cfg.GraphQL.Auth.Domains,
+		Prefixes:     []string{cfg.Bitsong.AccountPrefix, cfg.Osmosis.AccountPrefix},
+		Nonces:       nonces,
+		RequireNonce: cfg.GraphQL.Auth.RequireNonce,
+	}
+	authMiddleware := auth.Middleware(auth.Web3TokenVerifier(verifier), cfg.GraphQL.PlaygroundPass)
 
 	// new custom handler based on gqlgen version 0.11.3
 	queryHandler := handler.New(generated.NewExecutableSchema(config))
 
 	// queryHandler.Use(&debug.Tracer{})
+	queryHandler.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: auth.CheckOrigin(cfg.GraphQL.Auth.Domains),
+		},
+	})
 	queryHandler.AddTransport(transport.POST{})
 	queryHandler.AddTransport(transport.MultipartForm{})
 	queryHandler.SetQueryCache(lru.New(1000))
 	queryHandler.Use(extension.AutomaticPersistedQuery{Cache: lru.New(100)})
 	queryHandler.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
 		rc := graphql.GetOperationContext(ctx)
-		if playgroundPassword == cfg.GraphQL.PlaygroundPass {
-			rc.DisableIntrospection = false
-		} else {
-			rc.DisableIntrospection = true
+		rc.DisableIntrospection = !auth.IntrospectionFromContext(ctx)
+		// a subscription lives past the request, its events are not cached
+		if rc.Operation != nil && rc.Operation.Operation != ast.Subscription {
+			ctx = graph.WithPoolStatsLoader(ctx)
 		}
 		return next(ctx)
 	})
 
 	e.GET("/", echo.WrapHandler(playground.Handler("GraphQL Playground", cfg.GraphQL.Endpoint)))
 	//e.POST("/query", echo.WrapHandler(dataloader.DataLoaderMiddleware(queryHandler)))
-	e.POST("/query", echo.WrapHandler(queryHandler))
+	e.POST("/query", echo.WrapHandler(queryHandler), authMiddleware)
+	// subscriptions upgrade a GET request to a websocket
+	e.GET("/query", echo.WrapHandler(queryHandler), authMiddleware)
+	e.GET("/auth/nonce", auth.NonceHandler(nonces, nonceTTL, cfg.GraphQL.Auth.GetNonceLimit()))
 }
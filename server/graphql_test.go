@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/angelorc/sinfonia-go/server/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthDirective(t *testing.T) {
+	next := func(ctx context.Context) (interface{}, error) {
+		principal, _ := auth.PrincipalFromContext(ctx)
+		return principal.Address, nil
+	}
+
+	_, err := authDirective(context.Background(), nil, next)
+	require.EqualError(t, err, "access denied")
+
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{Address: "bitsong1test"})
+	res, err := authDirective(ctx, nil, next)
+	require.NoError(t, err)
+	require.Equal(t, "bitsong1test", res)
+}
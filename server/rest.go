@@ -1,14 +1,80 @@
 package server
 
 import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/modelv2"
+	"github.com/angelorc/sinfonia-go/mongo/repository"
+	"github.com/angelorc/sinfonia-go/server/graph"
 	"github.com/labstack/echo"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"net/http"
 )
 
-func InitRest(e *echo.Echo) {
+func InitRest(cfg config.Config, e *echo.Echo) {
 	e.GET("/healthz", func(c echo.Context) error {
 		return c.String(http.StatusOK, "<3")
 	})
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+	e.GET("/leaderboard.csv", leaderboardCSV(cfg))
+}
+
+// leaderboardCSV exports a leaderboard, it takes the arguments of the
+// leaderboard query: chain_id, metric, window, pool_id and limit.
+func leaderboardCSV(cfg config.Config) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var poolID, limit *int
+		for name, arg := range map[string]**int{"pool_id": &poolID, "limit": &limit} {
+			value := c.QueryParam(name)
+			if value == "" {
+				continue
+			}
+
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s %s", name, value))
+			}
+			*arg = &n
+		}
+
+		filter, err := modelv2.NewLeaderboardFilter(c.QueryParam("metric"), c.QueryParam("window"), poolID, limit, time.Now())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		var chainID *string
+		if value := c.QueryParam("chain_id"); value != "" {
+			chainID = &value
+		}
+		filter.ChainID = graph.LeaderboardChainID(cfg, filter.Metric, chainID)
+
+		entries, err := repository.NewLeaderboardRepository().Top(filter)
+		if err != nil {
+			return err
+		}
+
+		filename := fmt.Sprintf("leaderboard-%s-%s.csv", filter.Metric, c.QueryParam("window"))
+		c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+		c.Response().WriteHeader(http.StatusOK)
+
+		w := csv.NewWriter(c.Response())
+		w.Write([]string{"rank", "account", "account_key", "value", "count"})
+		for _, entry := range entries {
+			w.Write([]string{
+				strconv.Itoa(entry.Rank),
+				entry.Account,
+				entry.AccountKey,
+				strconv.FormatFloat(entry.Value, 'f', -1, 64),
+				strconv.FormatInt(entry.Count, 10),
+			})
+		}
+		w.Flush()
+
+		return w.Error()
+	}
 }
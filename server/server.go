@@ -3,9 +3,12 @@ package server
 import (
 	"context"
 	"github.com/angelorc/sinfonia-go/config"
+	"github.com/angelorc/sinfonia-go/mongo/db"
+	"github.com/angelorc/sinfonia-go/mongo/pubsub"
 	"github.com/angelorc/sinfonia-go/utility"
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
+	"log"
 	"os"
 	"os/signal"
 	"time"
@@ -24,7 +27,20 @@ func Start(cfg config.Config) {
 	InitGraphql(cfg, e)
 
 	// Load routes from rest
-	InitRest(e)
+	InitRest(cfg, e)
+
+	// Feed the subscriptions with the documents written by the indexer
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	if cfg.GraphQL.ChangeStreams {
+		go func() {
+			err := pubsub.Default().Watch(watchCtx, db.GetDB("default"), pubsub.Topics...)
+			if err != nil {
+				log.Printf("failed to watch the change streams, subscriptions are fed by this process only. err: %v", err)
+			}
+		}()
+	}
 
 	// Parse server routes
 	go func() {
@@ -0,0 +1,79 @@
+package web3token
+
+import (
+	"sync"
+	"time"
+
+	"github.com/angelorc/sinfonia-go/utility"
+)
+
+// ErrInvalidNonce is returned when a nonce was not issued, is expired or is
+// used by another token, it is the error of every NonceStore.
+var ErrInvalidNonce = utility.ErrInvalidNonce
+
+// NonceStore issues the nonces of the tokens and keeps the used ones.
+type NonceStore interface {
+	// Issue returns a new nonce valid for ttl and its expiry.
+	Issue(ttl time.Duration) (string, time.Time, error)
+	// Consume binds an unused nonce to token until expireAt, the nonce is
+	// then accepted again for the same token only. It fails with
+	// ErrInvalidNonce when the nonce can not be used.
+	Consume(nonce, token string, expireAt time.Time) error
+}
+
+// MemoryNonceStore keeps the nonces in memory, for a single server process
+// and the tests.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]*memoryNonce
+}
+
+type memoryNonce struct {
+	expireAt time.Time
+	// token the nonce is bound to, empty when unused
+	token string
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]*memoryNonce)}
+}
+
+func (s *MemoryNonceStore) Issue(ttl time.Duration) (string, time.Time, error) {
+	nonce, err := utility.NewNonce()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// drop the expired nonces
+	now := time.Now()
+	for n, issued := range s.nonces {
+		if now.After(issued.expireAt) {
+			delete(s.nonces, n)
+		}
+	}
+
+	expireAt := now.Add(ttl)
+	s.nonces[nonce] = &memoryNonce{expireAt: expireAt}
+
+	return nonce, expireAt, nil
+}
+
+func (s *MemoryNonceStore) Consume(nonce, token string, expireAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued, ok := s.nonces[nonce]
+	if !ok || time.Now().After(issued.expireAt) || (issued.token != "" && issued.token != token) {
+		return ErrInvalidNonce
+	}
+
+	issued.token = token
+	if expireAt.After(issued.expireAt) {
+		issued.expireAt = expireAt
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package web3token
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+var amino = codec.NewLegacyAmino()
+
+func init() {
+	amino.RegisterConcrete(&MsgSignData{}, "sign/MsgSignData", nil)
+}
+
+// MsgSignData is the ADR-036 message signing arbitrary data off-chain. The
+// signer is kept as a string so that the addresses of every prefix are
+// encoded as signed.
+type MsgSignData struct {
+	Signer string `json:"signer"`
+	Data   []byte `json:"data"`
+}
+
+var _ sdk.Msg = &MsgSignData{}
+
+func (m *MsgSignData) Reset()         { *m = MsgSignData{} }
+func (m *MsgSignData) String() string { return fmt.Sprintf("MsgSignData{%s}", m.Signer) }
+func (m *MsgSignData) ProtoMessage()  {}
+
+func (m *MsgSignData) Route() string { return "sign" }
+func (m *MsgSignData) Type() string  { return "MsgSignData" }
+
+func (m *MsgSignData) ValidateBasic() error {
+	if m.Signer == "" {
+		return errors.New("empty signer")
+	}
+
+	return nil
+}
+
+func (m *MsgSignData) GetSigners() []sdk.AccAddress {
+	_, bz, err := bech32.DecodeAndConvert(m.Signer)
+	if err != nil {
+		return nil
+	}
+
+	return []sdk.AccAddress{bz}
+}
+
+// GetSignBytes returns the sorted amino json of the message.
+func (m *MsgSignData) GetSignBytes() []byte {
+	return sdk.MustSortJSON(amino.MustMarshalJSON(m))
+}
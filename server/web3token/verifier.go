@@ -0,0 +1,65 @@
+package web3token
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Verifier verifies the tokens signed for one of the domains by an address
+// with one of the prefixes.
+type Verifier struct {
+	Domains  []string
+	Prefixes []string
+	// Nonces consumes the nonces of the tokens, the tokens with a nonce are
+	// rejected when it is nil. RequireNonce rejects the tokens without one.
+	Nonces       NonceStore
+	RequireNonce bool
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// Verify decodes the token of a bearer and verifies it, the nonce of the
+// token is bound to it once all the other checks pass. A token with a nonce
+// can be used by many requests until it expires, while another token can not
+// use the same nonce.
+func (v *Verifier) Verify(bearer string) (*Web3Token, error) {
+	token, err := ParseBearer(bearer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.ValidateSignature() {
+		return nil, errors.New("wrong signature")
+	}
+
+	if err := token.ValidateSigner(v.Prefixes); err != nil {
+		return nil, err
+	}
+
+	if !contains(v.Domains, token.GetDomain()) {
+		return nil, fmt.Errorf("domain %s is not allowed", token.GetDomain())
+	}
+
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if err := token.ValidateTimes(now); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case token.Payload.Nonce != "":
+		if v.Nonces == nil {
+			return nil, errors.New("nonces are not enabled")
+		}
+		if err := v.Nonces.Consume(token.Payload.Nonce, token.Signature, time.Unix(token.Payload.ExpireAt, 0)); err != nil {
+			return nil, err
+		}
+	case v.RequireNonce:
+		return nil, errors.New("missing nonce")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,142 @@
+package web3token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+)
+
+// signBearer signs payload with priv for the address of prefix.
+func signBearer(t *testing.T, priv *secp256k1.PrivKey, prefix string, payload Payload) string {
+	signer, err := bech32.ConvertAndEncode(prefix, priv.PubKey().Address())
+	require.NoError(t, err)
+
+	token := &Web3Token{
+		Signer:  signer,
+		Payload: payload,
+		PubKey: PubKey{
+			Type:  "tendermint/PubKeySecp256k1",
+			Value: base64.StdEncoding.EncodeToString(priv.PubKey().Bytes()),
+		},
+	}
+
+	sig, err := priv.Sign(token.SignDoc())
+	require.NoError(t, err)
+	token.Signature = base64.StdEncoding.EncodeToString(sig)
+
+	bz, err := json.Marshal(token)
+	require.NoError(t, err)
+
+	return "Bearer " + base64.StdEncoding.EncodeToString(bz)
+}
+
+func TestSignDoc(t *testing.T) {
+	token := &Web3Token{
+		Signer:  "bitsong1zq68dx423frv8yss5skqyg5um97vpefefe2enq",
+		Payload: Payload{Domain: "test.com", ExpireAt: 1657293505},
+	}
+
+	// the sign doc of keplr signArbitrary, with sorted keys
+	want := `{"account_number":"0","chain_id":"","fee":{"amount":[],"gas":"0"},"memo":"","msgs":[{"type":"sign/MsgSignData","value":{"data":"eyJkb21haW4iOiJ0ZXN0LmNvbSIsImV4cGlyZV9hdCI6MTY1NzI5MzUwNX0=","signer":"bitsong1zq68dx423frv8yss5skqyg5um97vpefefe2enq"}}],"sequence":"0"}`
+	require.Equal(t, want, string(token.SignDoc()))
+}
+
+func TestVerifier(t *testing.T) {
+	now := time.Now()
+	priv := secp256k1.GenPrivKey()
+	other := secp256k1.GenPrivKey()
+
+	verifier := &Verifier{
+		Domains:  []string{"sinfonia.zone"},
+		Prefixes: []string{"bitsong", "osmo"},
+		Nonces:   NewMemoryNonceStore(),
+	}
+	payload := Payload{Domain: "sinfonia.zone", ExpireAt: now.Add(5 * time.Minute).Unix()}
+
+	for _, prefix := range []string{"bitsong", "osmo"} {
+		token, err := verifier.Verify(signBearer(t, priv, prefix, payload))
+		require.NoError(t, err, prefix)
+		require.Equal(t, prefix, token.Signer[:len(prefix)])
+	}
+
+	_, err := verifier.Verify(signBearer(t, priv, "cosmos", payload))
+	require.Error(t, err, "prefix not allowed")
+
+	// a token signed by a key for the address of another key
+	bearer := signBearer(t, other, "bitsong", payload)
+	token, err := ParseBearer(bearer)
+	require.NoError(t, err)
+	token.Signer, _ = bech32.ConvertAndEncode("bitsong", priv.PubKey().Address())
+	sig, err := other.Sign(token.SignDoc())
+	require.NoError(t, err)
+	token.Signature = base64.StdEncoding.EncodeToString(sig)
+	bz, _ := json.Marshal(token)
+	_, err = verifier.Verify("Bearer " + base64.StdEncoding.EncodeToString(bz))
+	require.Error(t, err, "signer of another key")
+
+	wrongDomain := payload
+	wrongDomain.Domain = "test.com"
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", wrongDomain))
+	require.Error(t, err, "domain not allowed")
+
+	expired := payload
+	expired.ExpireAt = now.Add(-time.Minute).Unix()
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", expired))
+	require.Error(t, err, "expired")
+
+	future := payload
+	future.IssuedAt = now.Add(5 * time.Minute).Unix()
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", future))
+	require.Error(t, err, "issued in the future")
+
+	notBefore := payload
+	notBefore.NotBefore = now.Add(3 * time.Minute).Unix()
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", notBefore))
+	require.Error(t, err, "not valid yet")
+
+	issued := payload
+	issued.IssuedAt = now.Unix()
+	issued.NotBefore = now.Unix()
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", issued))
+	require.NoError(t, err)
+}
+
+func TestVerifier_Nonce(t *testing.T) {
+	nonces := NewMemoryNonceStore()
+	verifier := &Verifier{
+		Domains:      []string{"sinfonia.zone"},
+		Prefixes:     []string{"bitsong"},
+		Nonces:       nonces,
+		RequireNonce: true,
+	}
+	priv := secp256k1.GenPrivKey()
+	payload := Payload{Domain: "sinfonia.zone", ExpireAt: time.Now().Add(5 * time.Minute).Unix()}
+
+	_, err := verifier.Verify(signBearer(t, priv, "bitsong", payload))
+	require.EqualError(t, err, "missing nonce")
+
+	payload.Nonce = "not-issued"
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", payload))
+	require.ErrorIs(t, err, ErrInvalidNonce)
+
+	payload.Nonce, _, err = nonces.Issue(time.Minute)
+	require.NoError(t, err)
+	bearer := signBearer(t, priv, "bitsong", payload)
+
+	_, err = verifier.Verify(bearer)
+	require.NoError(t, err)
+
+	// the token is used by the following requests
+	_, err = verifier.Verify(bearer)
+	require.NoError(t, err)
+
+	// another token reusing the nonce
+	payload.ExpireAt++
+	_, err = verifier.Verify(signBearer(t, priv, "bitsong", payload))
+	require.ErrorIs(t, err, ErrInvalidNonce)
+}
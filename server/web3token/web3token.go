@@ -1,13 +1,15 @@
 package web3token
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	appparams "github.com/bitsongofficial/go-bitsong/app/params"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/x/auth/legacy/legacytx"
 	"strings"
 	"time"
 )
@@ -35,6 +37,8 @@ https://jsfiddle.net/dz4kh6nv/1/
 
 const (
 	MaxTimeLength = time.Minute * 10
+	// ClockSkew is the tolerance on the issued-at and not-before claims
+	ClockSkew = time.Minute
 )
 
 type Web3Token struct {
@@ -44,9 +48,17 @@ type Web3Token struct {
 	Signature string  `json:"signature"`
 }
 
+// Payload is the data signed with ADR-036, it is signed as its json encoding
+// with the fields in this order, the optional claims are omitted when empty.
 type Payload struct {
 	Domain   string `json:"domain"`
 	ExpireAt int64  `json:"expire_at"`
+	// IssuedAt and NotBefore are optional unix times
+	IssuedAt  int64 `json:"issued_at,omitempty"`
+	NotBefore int64 `json:"not_before,omitempty"`
+	// Nonce is an optional nonce issued by the server, a token with a nonce
+	// is accepted once
+	Nonce string `json:"nonce,omitempty"`
 }
 
 type PubKey struct {
@@ -54,7 +66,8 @@ type PubKey struct {
 	Value string `json:"value"`
 }
 
-func NewWeb3TokenFromBearer(bearer string) (*Web3Token, error) {
+// ParseBearer decodes the token of a bearer without verifying it.
+func ParseBearer(bearer string) (*Web3Token, error) {
 	bearerParts := strings.Split(bearer, "Bearer ")
 
 	if len(bearerParts) < 2 {
@@ -73,6 +86,17 @@ func NewWeb3TokenFromBearer(bearer string) (*Web3Token, error) {
 		return nil, err
 	}
 
+	return web3token, nil
+}
+
+// NewWeb3TokenFromBearer decodes the token of a bearer and verifies its
+// signature and its times, see Verifier for the other checks.
+func NewWeb3TokenFromBearer(bearer string) (*Web3Token, error) {
+	web3token, err := ParseBearer(bearer)
+	if err != nil {
+		return nil, err
+	}
+
 	if ok := web3token.Validate(); !ok {
 		return nil, errors.New("wrong signature")
 	}
@@ -80,47 +104,31 @@ func NewWeb3TokenFromBearer(bearer string) (*Web3Token, error) {
 	return web3token, nil
 }
 
+// SignDoc returns the ADR-036 amino json sign doc of the payload.
 func (w3t *Web3Token) SignDoc() []byte {
 	payloadBz, err := json.Marshal(w3t.Payload)
 	if err != nil {
 		return nil
 	}
 
-	data := base64.StdEncoding.EncodeToString(payloadBz)
+	msg := &MsgSignData{Signer: w3t.Signer, Data: payloadBz}
 
-	signDoc := strings.TrimSpace(fmt.Sprintf(`
-{
-  "chain_id": "",
-  "account_number": "0",
-  "sequence": "0",
-  "fee": {
-    "gas": "0",
-    "amount": []
-  },
-  "msgs": [
-    {
-      "type": "sign/MsgSignData",
-      "value": {
-        "signer": "%s",
-        "data": "%s"
-      }
-    }
-  ],
-  "memo": ""
-}
-`, w3t.Signer, data))
-
-	return []byte(signDoc)
+	return legacytx.StdSignBytes("", 0, 0, 0, legacytx.StdFee{}, []sdk.Msg{msg}, "")
 }
 
+// GetAddress returns the address of the signing key.
 func (w3t *Web3Token) GetAddress() sdk.AccAddress {
-	appparams.SetAddressPrefixes()
-	return w3t.GetPubKey().Address().Bytes()
+	pubKey := w3t.GetPubKey()
+	if pubKey == nil {
+		return nil
+	}
+
+	return pubKey.Address().Bytes()
 }
 
 func (w3t *Web3Token) GetPubKey() *secp256k1.PubKey {
 	pkBz, err := base64.StdEncoding.DecodeString(w3t.PubKey.Value)
-	if err != nil {
+	if err != nil || len(pkBz) != secp256k1.PubKeySize {
 		return nil
 	}
 
@@ -137,18 +145,35 @@ func (w3t *Web3Token) GetSignature() []byte {
 }
 
 func (w3t *Web3Token) GetMsg() []byte {
-	var msg map[string]interface{}
+	return w3t.SignDoc()
+}
 
-	if err := json.Unmarshal(w3t.SignDoc(), &msg); err != nil {
-		return nil
+func (w3t *Web3Token) ValidateSignature() bool {
+	pubKey := w3t.GetPubKey()
+	if pubKey == nil {
+		return false
 	}
 
-	msgBz, _ := json.Marshal(msg)
-	return msgBz
+	return pubKey.VerifySignature(w3t.GetMsg(), w3t.GetSignature())
 }
 
-func (w3t *Web3Token) ValidateSignature() bool {
-	return w3t.GetPubKey().VerifySignature(w3t.GetMsg(), w3t.GetSignature())
+// ValidateSigner checks that the signer is the address of the signing key
+// with one of the prefixes.
+func (w3t *Web3Token) ValidateSigner(prefixes []string) error {
+	prefix, bz, err := bech32.DecodeAndConvert(w3t.Signer)
+	if err != nil {
+		return fmt.Errorf("invalid signer %s", w3t.Signer)
+	}
+
+	if !contains(prefixes, prefix) {
+		return fmt.Errorf("signer prefix %s is not allowed", prefix)
+	}
+
+	if !bytes.Equal(bz, w3t.GetAddress()) {
+		return fmt.Errorf("signer %s is not the address of the pub key", w3t.Signer)
+	}
+
+	return nil
 }
 
 func (w3t *Web3Token) GetDomain() string {
@@ -163,6 +188,48 @@ func (w3t *Web3Token) IsExpired() bool {
 	return minTime.After(parsed) || maxTime.Before(parsed)
 }
 
+// ValidateTimes checks the expiry and the optional issued-at and not-before
+// claims at time now. A token lasts at most MaxTimeLength.
+func (w3t *Web3Token) ValidateTimes(now time.Time) error {
+	expireAt := time.Unix(w3t.Payload.ExpireAt, 0)
+
+	if now.After(expireAt) {
+		return errors.New("token expired")
+	}
+
+	if now.Add(MaxTimeLength).Before(expireAt) {
+		return fmt.Errorf("token lasts more than %s", MaxTimeLength)
+	}
+
+	if w3t.Payload.IssuedAt != 0 {
+		issuedAt := time.Unix(w3t.Payload.IssuedAt, 0)
+
+		if issuedAt.After(now.Add(ClockSkew)) {
+			return errors.New("token issued in the future")
+		}
+
+		if expireAt.Sub(issuedAt) > MaxTimeLength || !expireAt.After(issuedAt) {
+			return errors.New("invalid token issued-at")
+		}
+	}
+
+	if w3t.Payload.NotBefore != 0 && now.Add(ClockSkew).Before(time.Unix(w3t.Payload.NotBefore, 0)) {
+		return errors.New("token not valid yet")
+	}
+
+	return nil
+}
+
 func (w3t *Web3Token) Validate() bool {
-	return w3t.ValidateSignature() && !w3t.IsExpired()
+	return w3t.ValidateSignature() && w3t.ValidateTimes(time.Now()) == nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
 }
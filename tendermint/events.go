@@ -0,0 +1,56 @@
+package tendermint
+
+import (
+	"context"
+
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// SubscribeNewBlocks subscribes to the NewBlock events through the websocket
+// endpoint and streams the height of every new block. The returned channel is
+// closed when ctx is done or when the subscription is dropped by the node.
+func SubscribeNewBlocks(ctx context.Context, client rpcclient.Client, subscriber string) (<-chan int64, error) {
+	if !client.IsRunning() {
+		if err := client.Start(); err != nil {
+			return nil, err
+		}
+	}
+
+	query := tmtypes.QueryForEvent(tmtypes.EventNewBlock).String()
+	eventsCh, err := client.Subscribe(ctx, subscriber, query)
+	if err != nil {
+		return nil, err
+	}
+
+	heights := make(chan int64)
+
+	go func() {
+		defer close(heights)
+		defer client.UnsubscribeAll(context.Background(), subscriber)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case evt, ok := <-eventsCh:
+				if !ok {
+					return
+				}
+
+				data, ok := evt.Data.(tmtypes.EventDataNewBlock)
+				if !ok || data.Block == nil {
+					continue
+				}
+
+				select {
+				case heights <- data.Block.Height:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return heights, nil
+}
@@ -0,0 +1,65 @@
+package utility
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cosmos/btcutil/bech32"
+)
+
+// The addresses are encoded with the bech32 of the cosmos-sdk, the chain
+// clients encode the signers of the indexed messages with EncodeAddressBytes
+// so that an address has a single encoding.
+
+// AddressKey returns the canonical key of a bech32 address, the hex of the
+// address bytes. The addresses of a public key on every chain have the same
+// key, only the bech32 prefix differs.
+func AddressKey(address string) (string, error) {
+	_, data, err := bech32.Decode(address, bech32.MaxLengthBIP173)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	bz, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", fmt.Errorf("invalid address %s: %w", address, err)
+	}
+
+	return hex.EncodeToString(bz), nil
+}
+
+// EncodeAddressKey returns the bech32 address of key with the given prefix.
+func EncodeAddressKey(key, prefix string) (string, error) {
+	bz, err := hex.DecodeString(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid address key %s: %w", key, err)
+	}
+
+	return EncodeAddressBytes(prefix, bz)
+}
+
+// EncodeAddressBytes returns the bech32 address of the address bytes bz with
+// the given prefix, the address of no bytes is empty.
+func EncodeAddressBytes(prefix string, bz []byte) (string, error) {
+	if len(bz) == 0 {
+		return "", nil
+	}
+
+	data, err := bech32.ConvertBits(bz, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("invalid address bytes %X: %w", bz, err)
+	}
+
+	return bech32.Encode(prefix, data)
+}
+
+// ConvertBech32Prefix re-encodes a bech32 address with another prefix, e.g.
+// the osmosis address of a bitsong account derived from the same key.
+func ConvertBech32Prefix(address, prefix string) (string, error) {
+	key, err := AddressKey(address)
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeAddressKey(key, prefix)
+}
@@ -0,0 +1,57 @@
+package utility
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAddressKey(t *testing.T) {
+	const (
+		osmo    = "osmo1cyyzpxplxdzkeea7kwsydadg87357qnahakaks"
+		bitsong = "bitsong1cyyzpxplxdzkeea7kwsydadg87357qnan0h9zd"
+	)
+
+	osmoKey, err := AddressKey(osmo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitsongKey, err := AddressKey(bitsong)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if osmoKey != bitsongKey {
+		t.Fatalf("keys differ: %s != %s", osmoKey, bitsongKey)
+	}
+	if len(osmoKey) != 40 {
+		t.Fatalf("expected the key of 20 bytes, got %s", osmoKey)
+	}
+
+	address, err := EncodeAddressKey(osmoKey, "bitsong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != bitsong {
+		t.Fatalf("expected %s, got %s", bitsong, address)
+	}
+
+	bz, _ := hex.DecodeString(osmoKey)
+	if address, err := EncodeAddressBytes("osmo", bz); err != nil || address != osmo {
+		t.Fatalf("expected %s, got %s (%v)", osmo, address, err)
+	}
+	if address, err := EncodeAddressBytes("osmo", nil); err != nil || address != "" {
+		t.Fatalf("expected no address, got %s (%v)", address, err)
+	}
+
+	converted, err := ConvertBech32Prefix(bitsong, "osmo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if converted != osmo {
+		t.Fatalf("expected %s, got %s", osmo, converted)
+	}
+
+	if _, err := AddressKey("osmo1invalid"); err == nil {
+		t.Fatal("expected an error for an invalid address")
+	}
+}
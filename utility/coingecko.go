@@ -5,53 +5,128 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 )
 
 const (
-	CoingeckoEndpoint = "https://api.coingecko.com/api/v3/coins/{id}/market_chart/range?vs_currency={currency}&from={from}&to={to}"
+	CoingeckoEndpoint    = "https://api.coingecko.com/api/v3"
+	CoingeckoProEndpoint = "https://pro-api.coingecko.com/api/v3"
+
+	// CoingeckoPublicRateLimit is the number of requests per minute allowed by
+	// the public api
+	CoingeckoPublicRateLimit = 10
+
+	coingeckoRetries = 3
 )
 
 type HistoryResponse struct {
 	Prices [][2]float64 `json:"prices"`
 }
 
-func GetHistoricalCoinPrice(id, vsCurrency string, startTime, endTime time.Time) ([][2]float64, error) {
-	price, err := getPriceFromAPI(id, vsCurrency, startTime, endTime)
-	if err != nil {
-		return [][2]float64{{0, 0}}, err
-	}
+// CoingeckoClient queries the coingecko api spacing the requests to stay in
+// the rate limit, the requests limited with a 429 are retried.
+type CoingeckoClient struct {
+	endpoint string
+	apiKey   string
+	interval time.Duration
+	http     *http.Client
 
-	return price, nil
+	mu   sync.Mutex
+	last time.Time
 }
 
-func getPriceFromAPI(id, vsCurrency string, startTime, endTime time.Time) ([][2]float64, error) {
-	endpoint := strings.ReplaceAll(CoingeckoEndpoint, "{id}", id)
-	endpoint = strings.ReplaceAll(endpoint, "{currency}", vsCurrency)
-	endpoint = strings.ReplaceAll(endpoint, "{from}", fmt.Sprintf("%d", startTime.Unix()))
-	endpoint = strings.ReplaceAll(endpoint, "{to}", fmt.Sprintf("%d", endTime.Unix()))
-
-	res, err := http.Get(endpoint)
-	if err != nil {
-		return [][2]float64{{0, 0}}, err
+// NewCoingeckoClient returns a client of the public api, or of the pro api
+// when apiKey is set. requestsPerMinute defaults to the public rate limit.
+func NewCoingeckoClient(apiKey string, requestsPerMinute int) *CoingeckoClient {
+	if requestsPerMinute <= 0 {
+		requestsPerMinute = CoingeckoPublicRateLimit
 	}
-	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		return [][2]float64{{0, 0}}, fmt.Errorf("bad token price history response: status %d", res.StatusCode)
+	endpoint := CoingeckoEndpoint
+	if apiKey != "" {
+		endpoint = CoingeckoProEndpoint
 	}
 
-	bz, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return [][2]float64{{0, 0}}, err
+	return &CoingeckoClient{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		http:     &http.Client{Timeout: 30 * time.Second},
 	}
+}
+
+// History returns the [timestamp in ms, price] samples of the coin id between
+// startTime and endTime. The granularity depends on the range: 5 minutes up to
+// a day, hourly up to 90 days and daily above.
+func (c *CoingeckoClient) History(id, vsCurrency string, startTime, endTime time.Time) ([][2]float64, error) {
+	query := url.Values{}
+	query.Set("vs_currency", vsCurrency)
+	query.Set("from", strconv.FormatInt(startTime.Unix(), 10))
+	query.Set("to", strconv.FormatInt(endTime.Unix(), 10))
 
 	var response HistoryResponse
-	err = json.Unmarshal(bz, &response)
-	if err != nil {
-		return [][2]float64{{0, 0}}, err
+	if err := c.get(fmt.Sprintf("/coins/%s/market_chart/range", url.PathEscape(id)), query, &response); err != nil {
+		return nil, err
 	}
 
 	return response.Prices, nil
 }
+
+func (c *CoingeckoClient) get(path string, query url.Values, out interface{}) error {
+	if c.apiKey != "" {
+		query.Set("x_cg_pro_api_key", c.apiKey)
+	}
+	endpoint := c.endpoint + path + "?" + query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		c.wait()
+
+		res, err := c.http.Get(endpoint)
+		if err != nil {
+			return err
+		}
+
+		bz, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case res.StatusCode == http.StatusTooManyRequests && attempt < coingeckoRetries:
+			c.backoff(res.Header.Get("Retry-After"))
+			continue
+		case res.StatusCode != http.StatusOK:
+			return fmt.Errorf("bad coingecko response %s: status %d", path, res.StatusCode)
+		}
+
+		return json.Unmarshal(bz, out)
+	}
+}
+
+// wait blocks until the next request can be sent.
+func (c *CoingeckoClient) wait() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if next := c.last.Add(c.interval); time.Now().Before(next) {
+		time.Sleep(time.Until(next))
+	}
+	c.last = time.Now()
+}
+
+// backoff delays the next request by the seconds of retryAfter, or by a
+// minute when it is not set.
+func (c *CoingeckoClient) backoff(retryAfter string) {
+	delay := time.Minute
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	}
+
+	c.mu.Lock()
+	c.last = time.Now().Add(delay - c.interval)
+	c.mu.Unlock()
+}
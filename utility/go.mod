@@ -3,6 +3,7 @@ module github.com/angelorc/sinfonia-go/utility
 go 1.18
 
 require (
+	github.com/cosmos/btcutil v1.0.4
 	github.com/go-playground/locales v0.14.0 // indirect
 	github.com/go-playground/universal-translator v0.18.0 // indirect
 	github.com/go-playground/validator v9.31.0+incompatible // indirect
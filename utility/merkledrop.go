@@ -0,0 +1,90 @@
+package utility
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+)
+
+// MerkledropLeaf returns the leaf of a merkledrop entry as hashed by the
+// bitsong merkledrop module: the sha256 of the index, the bech32 address and
+// the amount, eg: 0bitsong1...1000.
+func MerkledropLeaf(index uint64, address, amount string) []byte {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%d%s%s", index, address, amount)))
+	return h[:]
+}
+
+// MerkledropHashPair returns the parent of two nodes, the smaller node is
+// hashed first so that a proof does not need the side of its nodes.
+func MerkledropHashPair(a, b []byte) []byte {
+	h := sha256.New()
+	if bytes.Compare(a, b) < 0 {
+		h.Write(a)
+		h.Write(b)
+	} else {
+		h.Write(b)
+		h.Write(a)
+	}
+
+	return h.Sum(nil)
+}
+
+// VerifyMerkledropProof tells if the proof hashes leaf up to root.
+func VerifyMerkledropProof(leaf []byte, proof [][]byte, root []byte) bool {
+	hash := leaf
+	for _, p := range proof {
+		hash = MerkledropHashPair(hash, p)
+	}
+
+	return bytes.Equal(hash, root)
+}
+
+// MerkledropTree is the merkle tree of the leaves of a merkledrop, an odd
+// node is moved up to the next level without hashing.
+type MerkledropTree struct {
+	levels [][][]byte
+}
+
+// NewMerkledropTree builds the tree of leaves, the leaves keep their order.
+func NewMerkledropTree(leaves [][]byte) (*MerkledropTree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("no leaves")
+	}
+
+	levels := [][][]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, MerkledropHashPair(level[i], level[i+1]))
+		}
+
+		levels = append(levels, next)
+		level = next
+	}
+
+	return &MerkledropTree{levels: levels}, nil
+}
+
+func (t *MerkledropTree) Root() []byte {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof returns the proof of the leaf i, the sibling of its node at each
+// level.
+func (t *MerkledropTree) Proof(i int) [][]byte {
+	proof := make([][]byte, 0, len(t.levels)-1)
+
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := i ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		i /= 2
+	}
+
+	return proof
+}
@@ -0,0 +1,73 @@
+package utility
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func decodeHexes(t *testing.T, hexes ...string) [][]byte {
+	bzs := make([][]byte, len(hexes))
+	for i, h := range hexes {
+		bz, err := hex.DecodeString(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bzs[i] = bz
+	}
+
+	return bzs
+}
+
+// the proof of the bitsong merkledrop module tests
+func TestVerifyMerkledropProof(t *testing.T) {
+	root := decodeHexes(t, "5eb39dbca442a25db0f5d9e63489451b7bfc173796aa221e7207839de3a59e79")[0]
+	proof := decodeHexes(t,
+		"7f0b92cc8318e4fb0db9052325b474e2eabb80d79e6e1abab92093d3a88fe029",
+		"a258c32bee9b0bbb7a2d1999ab4698294844e7440aa6dcd067e0d5142fa20522",
+	)
+
+	leaf := MerkledropLeaf(0, "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2", "1000000")
+	if !VerifyMerkledropProof(leaf, proof, root) {
+		t.Error("VerifyMerkledropProof() = false, want true")
+	}
+
+	for _, leaf := range [][]byte{
+		MerkledropLeaf(1, "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2", "1000000"),
+		MerkledropLeaf(0, "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2", "1000001"),
+	} {
+		if VerifyMerkledropProof(leaf, proof, root) {
+			t.Error("VerifyMerkledropProof() = true for another leaf")
+		}
+	}
+}
+
+func TestMerkledropTree(t *testing.T) {
+	if _, err := NewMerkledropTree(nil); err == nil {
+		t.Error("NewMerkledropTree(nil) error = nil")
+	}
+
+	for n := 1; n <= 9; n++ {
+		leaves := make([][]byte, n)
+		for i := range leaves {
+			leaves[i] = MerkledropLeaf(uint64(i), "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2", "1000")
+		}
+
+		tree, err := NewMerkledropTree(leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, leaf := range leaves {
+			if !VerifyMerkledropProof(leaf, tree.Proof(i), tree.Root()) {
+				t.Errorf("proof of leaf %d of %d not matching the root", i, n)
+			}
+		}
+	}
+
+	// a single leaf is the root
+	leaf := MerkledropLeaf(0, "bitsong1vgpsha4f8grmsqr6krfdxwpcf3x20h0q3ztaj2", "1000")
+	tree, _ := NewMerkledropTree([][]byte{leaf})
+	if len(tree.Proof(0)) != 0 || !VerifyMerkledropProof(leaf, nil, tree.Root()) {
+		t.Error("the root of a single leaf is not the leaf")
+	}
+}
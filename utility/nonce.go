@@ -0,0 +1,21 @@
+package utility
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrInvalidNonce is returned by the nonce stores when a nonce was not
+// issued, is expired or was already used.
+var ErrInvalidNonce = errors.New("invalid nonce")
+
+// NewNonce returns a random hex nonce.
+func NewNonce() (string, error) {
+	bz := make([]byte, 16)
+	if _, err := rand.Read(bz); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(bz), nil
+}